@@ -29,6 +29,7 @@ import (
 	"net/http"
 	"os"
 	"runtime"
+	"runtime/metrics"
 	"sort"
 	"strconv"
 	"strings"
@@ -360,6 +361,29 @@ func memstats() any {
 	return *stats
 }
 
+// PublishRuntimeMetrics publishes the metrics supported by package
+// runtime/metrics under the name "go_runtime_metrics". It is not published
+// by default; programs that want it should call PublishRuntimeMetrics once,
+// typically from an init function. Like Publish, PublishRuntimeMetrics
+// panics if it is called again.
+//
+// The published Var reads the metrics afresh each time its value is
+// requested, and its value is the JSON object written by
+// metrics.WriteJSON, which maps the name of every metric returned by
+// metrics.All to its value.
+func PublishRuntimeMetrics() {
+	Publish("go_runtime_metrics", runtimeMetrics{})
+}
+
+// runtimeMetrics is the Var published by PublishRuntimeMetrics.
+type runtimeMetrics struct{}
+
+func (runtimeMetrics) String() string {
+	var b strings.Builder
+	metrics.WriteJSON(&b)
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
 func init() {
 	http.HandleFunc("/debug/vars", expvarHandler)
 	Publish("cmdline", Func(cmdline))
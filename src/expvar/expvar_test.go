@@ -464,6 +464,38 @@ func TestHandler(t *testing.T) {
 	}
 }
 
+func TestPublishRuntimeMetrics(t *testing.T) {
+	RemoveAll()
+	PublishRuntimeMetrics()
+	v := Get("go_runtime_metrics")
+	if v == nil {
+		t.Fatal("go_runtime_metrics not published")
+	}
+	runtime.GC()
+
+	var m map[string]any
+	if err := json.Unmarshal([]byte(v.String()), &m); err != nil {
+		t.Fatalf("unmarshaling go_runtime_metrics: %v", err)
+	}
+	const scalar = "/gc/cycles/total:gc-cycles"
+	if n, ok := m[scalar].(float64); !ok || n < 1 {
+		t.Errorf("%s = %v, want a number at least 1", scalar, m[scalar])
+	}
+	const hist = "/gc/pauses:seconds"
+	h, ok := m[hist].(map[string]any)
+	if !ok {
+		t.Fatalf("%s = %v, want an object", hist, m[hist])
+	}
+	buckets, _ := h["buckets"].([]any)
+	counts, _ := h["counts"].([]any)
+	if len(buckets) == 0 || len(counts) != len(buckets)-1 {
+		t.Errorf("%s has %d buckets and %d counts", hist, len(buckets), len(counts))
+	}
+	if len(buckets) > 0 && buckets[0] != "-Inf" {
+		t.Errorf("%s first bucket boundary = %v, want \"-Inf\"", hist, buckets[0])
+	}
+}
+
 func BenchmarkRealworldExpvarUsage(b *testing.B) {
 	var (
 		bytesSent Int
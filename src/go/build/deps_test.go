@@ -145,14 +145,14 @@ var depsRules = `
 	MATH
 	< math/rand;
 
-	MATH
-	< runtime/metrics;
-
 	MATH, unicode/utf8
 	< strconv;
 
 	unicode !< strconv;
 
+	MATH, context, io, sort, strconv, time
+	< runtime/metrics;
+
 	# STR is basic string and buffer manipulation.
 	RUNTIME, io, unicode/utf8, unicode/utf16, unicode
 	< bytes, strings
@@ -493,7 +493,11 @@ var depsRules = `
 
 	# HTTP-aware packages
 
-	encoding/json, net/http
+	# expvar publishes runtime/metrics for PublishRuntimeMetrics.
+	# That pulls in nothing net/http doesn't already, since
+	# runtime/metrics needs only MATH, context, io, sort,
+	# strconv, and time.
+	encoding/json, net/http, runtime/metrics
 	< expvar;
 
 	net/http, net/http/internal/ascii
@@ -30,6 +30,24 @@ const (
 	debugChan = false
 )
 
+// chanBlockTime is the total nanoseconds goroutines have spent parked
+// in channel sends, receives, and select statements, as counted by Ms
+// that have since exited. Other Ms count their own, in m.chanBlockTime.
+// Protected by sched.lock.
+var chanBlockTime int64
+
+// totalChanBlockTime returns the total nanoseconds goroutines have spent
+// parked in channel sends, receives, and select statements, across all Ms.
+func totalChanBlockTime() int64 {
+	lock(&sched.lock)
+	n := chanBlockTime
+	for mp := allm; mp != nil; mp = mp.alllink {
+		n += mp.chanBlockTime
+	}
+	unlock(&sched.lock)
+	return n
+}
+
 type hchan struct {
 	qcount   uint           // total data in the queue
 	dataqsiz uint           // size of the circular queue
@@ -256,6 +274,7 @@ func chansend(c *hchan, ep unsafe.Pointer, block bool, callerpc uintptr) bool {
 	// changes and when we set gp.activeStackChans is not safe for
 	// stack shrinking.
 	atomic.Store8(&gp.parkingOnChan, 1)
+	parkStart := nanotime()
 	gopark(chanparkcommit, unsafe.Pointer(&c.lock), waitReasonChanSend, traceEvGoBlockSend, 2)
 	// Ensure the value being sent is kept alive until the
 	// receiver copies it out. The sudog has a pointer to the
@@ -270,6 +289,7 @@ func chansend(c *hchan, ep unsafe.Pointer, block bool, callerpc uintptr) bool {
 	gp.waiting = nil
 	gp.activeStackChans = false
 	closed := !mysg.success
+	gp.m.chanBlockTime += nanotime() - parkStart
 	gp.param = nil
 	if mysg.releasetime > 0 {
 		blockevent(mysg.releasetime-t0, 2)
@@ -580,6 +600,7 @@ func chanrecv(c *hchan, ep unsafe.Pointer, block bool) (selected, received bool)
 	// changes and when we set gp.activeStackChans is not safe for
 	// stack shrinking.
 	atomic.Store8(&gp.parkingOnChan, 1)
+	parkStart := nanotime()
 	gopark(chanparkcommit, unsafe.Pointer(&c.lock), waitReasonChanReceive, traceEvGoBlockRecv, 2)
 
 	// someone woke us up
@@ -588,6 +609,7 @@ func chanrecv(c *hchan, ep unsafe.Pointer, block bool) (selected, received bool)
 	}
 	gp.waiting = nil
 	gp.activeStackChans = false
+	gp.m.chanBlockTime += nanotime() - parkStart
 	if mysg.releasetime > 0 {
 		blockevent(mysg.releasetime-t0, 2)
 	}
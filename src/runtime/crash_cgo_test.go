@@ -434,6 +434,28 @@ func TestCgoNumGoroutine(t *testing.T) {
 	}
 }
 
+func TestCgoOSStacksMetric(t *testing.T) {
+	t.Parallel()
+	got := runTestProg(t, "testprogcgo", "OSStacksMetric")
+	want := "OK\n"
+	if got != want {
+		t.Errorf("expected %q got %v", want, got)
+	}
+}
+
+func TestCgoInCgoMetric(t *testing.T) {
+	switch runtime.GOOS {
+	case "windows", "plan9":
+		t.Skipf("skipping in-cgo metric test on %s", runtime.GOOS)
+	}
+	t.Parallel()
+	got := runTestProg(t, "testprogcgo", "InCgoMetric")
+	want := "OK\n"
+	if got != want {
+		t.Errorf("expected %q got %v", want, got)
+	}
+}
+
 func TestCatchPanic(t *testing.T) {
 	t.Parallel()
 	switch runtime.GOOS {
@@ -1583,3 +1583,38 @@ func (s *ScavengeIndex) Mark(base, limit uintptr) {
 func (s *ScavengeIndex) Clear(ci ChunkIdx) {
 	s.i.clear(chunkIdx(ci))
 }
+
+var contendLock struct {
+	mu           mutex
+	locked, done uint32
+}
+
+// ContendRuntimeLock makes a new goroutine block trying to acquire a
+// runtime mutex held by the calling goroutine, and releases the mutex
+// only once the runtime has counted a contended lock. GOMAXPROCS must be
+// at least 2, and nothing may stop the world in the meantime, since the
+// calling goroutine can't be preempted while it holds the mutex.
+func ContendRuntimeLock() {
+	c := &contendLock
+	atomic.Store(&c.locked, 0)
+	atomic.Store(&c.done, 0)
+	go func() {
+		for atomic.Load(&c.locked) == 0 {
+			Gosched()
+		}
+		lock(&c.mu)
+		unlock(&c.mu)
+		atomic.Store(&c.done, 1)
+	}()
+
+	lock(&c.mu)
+	before := totalLockContentions()
+	atomic.Store(&c.locked, 1)
+	for totalLockContentions() == before {
+		osyield()
+	}
+	unlock(&c.mu)
+	for atomic.Load(&c.done) == 0 {
+		Gosched()
+	}
+}
@@ -32,6 +32,18 @@ func TestGcSys(t *testing.T) {
 	}
 }
 
+func TestGCSweepDone(t *testing.T) {
+	// The last sweeper to finish a sweep phase is responsible for
+	// reporting that the phase is done. Make sure that actually happens.
+	got := runTestProg(t, "testprog", "GCSweepDone", "GODEBUG=gcpacertrace=1")
+	if !strings.Contains(got, "pacer: sweep done") {
+		t.Fatalf("end of sweep phase not reported; got:\n%s", got)
+	}
+	if !strings.HasSuffix(got, "OK\n") {
+		t.Fatalf("expected output to end in %q, but got:\n%s", "OK\n", got)
+	}
+}
+
 func TestGcDeepNesting(t *testing.T) {
 	type T [2][2][2][2][2][2][2][2][2][2]*int
 	a := new(T)
@@ -64,7 +64,7 @@ func getitab(inter *interfacetype, typ *_type, canfail bool) *itab {
 	}
 
 	// Entry doesn't exist yet. Make a new entry & add it.
-	m = (*itab)(persistentalloc(unsafe.Sizeof(itab{})+uintptr(len(inter.mhdr)-1)*goarch.PtrSize, 0, &memstats.other_sys))
+	m = (*itab)(persistentalloc(unsafe.Sizeof(itab{})+uintptr(len(inter.mhdr)-1)*goarch.PtrSize, 0, &memstats.itabSys))
 	m.inter = inter
 	m._type = typ
 	// The hash is used in type switches. However, compiler statically generates itab's
@@ -328,7 +328,7 @@ func convT(t *_type, v unsafe.Pointer) unsafe.Pointer {
 	if asanenabled {
 		asanread(v, t.size)
 	}
-	x := mallocgc(t.size, t, true)
+	x := mallocgc1(t.size, t, true, true)
 	typedmemmove(t, x, v)
 	return x
 }
@@ -344,7 +344,7 @@ func convTnoptr(t *_type, v unsafe.Pointer) unsafe.Pointer {
 		asanread(v, t.size)
 	}
 
-	x := mallocgc(t.size, t, false)
+	x := mallocgc1(t.size, t, false, true)
 	memmove(x, v, t.size)
 	return x
 }
@@ -356,7 +356,7 @@ func convT16(val uint16) (x unsafe.Pointer) {
 			x = add(x, 6)
 		}
 	} else {
-		x = mallocgc(2, uint16Type, false)
+		x = mallocgc1(2, uint16Type, false, true)
 		*(*uint16)(x) = val
 	}
 	return
@@ -369,7 +369,7 @@ func convT32(val uint32) (x unsafe.Pointer) {
 			x = add(x, 4)
 		}
 	} else {
-		x = mallocgc(4, uint32Type, false)
+		x = mallocgc1(4, uint32Type, false, true)
 		*(*uint32)(x) = val
 	}
 	return
@@ -379,7 +379,7 @@ func convT64(val uint64) (x unsafe.Pointer) {
 	if val < uint64(len(staticuint64s)) {
 		x = unsafe.Pointer(&staticuint64s[val])
 	} else {
-		x = mallocgc(8, uint64Type, false)
+		x = mallocgc1(8, uint64Type, false, true)
 		*(*uint64)(x) = val
 	}
 	return
@@ -389,7 +389,7 @@ func convTstring(val string) (x unsafe.Pointer) {
 	if val == "" {
 		x = unsafe.Pointer(&zeroVal[0])
 	} else {
-		x = mallocgc(unsafe.Sizeof(val), stringType, true)
+		x = mallocgc1(unsafe.Sizeof(val), stringType, true, true)
 		*(*string)(x) = val
 	}
 	return
@@ -400,7 +400,7 @@ func convTslice(val []byte) (x unsafe.Pointer) {
 	if (*slice)(unsafe.Pointer(&val)).array == nil {
 		x = unsafe.Pointer(&zeroVal[0])
 	} else {
-		x = mallocgc(unsafe.Sizeof(val), sliceType, true)
+		x = mallocgc1(unsafe.Sizeof(val), sliceType, true, true)
 		*(*[]byte)(x) = val
 	}
 	return
@@ -61,6 +61,7 @@ func lock2(l *mutex) {
 	if v == mutex_unlocked {
 		return
 	}
+	gp.m.ncontended++
 
 	// wait is either MUTEX_LOCKED or MUTEX_SLEEPING
 	// depending on whether there is a thread sleeping
@@ -46,6 +46,7 @@ func lock2(l *mutex) {
 	if atomic.Casuintptr(&l.key, 0, locked) {
 		return
 	}
+	gp.m.ncontended++
 	semacreate(gp.m)
 
 	// On uniprocessor's, no point spinning.
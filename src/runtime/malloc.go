@@ -846,6 +846,12 @@ func (c *mcache) nextFree(spc spanClass) (v gclinkptr, s *mspan, shouldhelpgc bo
 // Small objects are allocated from the per-P cache's free lists.
 // Large objects (> 32 kB) are allocated straight from the heap.
 func mallocgc(size uintptr, typ *_type, needzero bool) unsafe.Pointer {
+	return mallocgc1(size, typ, needzero, false)
+}
+
+// mallocgc1 is mallocgc, but if iface is set, it also counts the
+// allocation as one that boxes a value converted to an interface.
+func mallocgc1(size uintptr, typ *_type, needzero, iface bool) unsafe.Pointer {
 	if gcphase == _GCmarktermination {
 		throw("mallocgc called with gcphase == _GCmarktermination")
 	}
@@ -929,6 +935,9 @@ func mallocgc(size uintptr, typ *_type, needzero bool) unsafe.Pointer {
 	if c == nil {
 		throw("mallocgc called without a P or outside bootstrapping")
 	}
+	if iface {
+		c.ifaceAllocs++
+	}
 	var span *mspan
 	var x unsafe.Pointer
 	noscan := typ == nil || typ.ptrdata == 0
@@ -993,10 +1002,14 @@ func mallocgc(size uintptr, typ *_type, needzero bool) unsafe.Pointer {
 				return x
 			}
 			// Allocate a new maxTinySize block.
+			c.tinyBlocks++
 			span = c.alloc[tinySpanClass]
 			v := nextFreeFast(span)
 			if v == 0 {
 				v, span, shouldhelpgc = c.nextFree(tinySpanClass)
+				c.slowAllocs++
+			} else {
+				c.fastAllocs++
 			}
 			x = unsafe.Pointer(v)
 			(*[2]uint64)(x)[0] = 0
@@ -1022,6 +1035,9 @@ func mallocgc(size uintptr, typ *_type, needzero bool) unsafe.Pointer {
 			v := nextFreeFast(span)
 			if v == 0 {
 				v, span, shouldhelpgc = c.nextFree(spc)
+				c.slowAllocs++
+			} else {
+				c.fastAllocs++
 			}
 			x = unsafe.Pointer(v)
 			if needzero && span.needzero != 0 {
@@ -1107,7 +1123,15 @@ func mallocgc(size uintptr, typ *_type, needzero bool) unsafe.Pointer {
 		if rate != 1 && size < c.nextSample {
 			c.nextSample -= size
 		} else {
-			profilealloc(mp, x, size)
+			// Attribute the allocation to the caller of the runtime
+			// function that allocated, skipping mallocgc too unless
+			// this is one of the interface conversions that call
+			// mallocgc1 directly.
+			skip := 5
+			if iface {
+				skip = 4
+			}
+			profilealloc(mp, x, size, skip)
 		}
 	}
 	mp.mallocing = 0
@@ -1225,13 +1249,13 @@ func reflect_unsafe_NewArray(typ *_type, n int) unsafe.Pointer {
 	return newarray(typ, n)
 }
 
-func profilealloc(mp *m, x unsafe.Pointer, size uintptr) {
+func profilealloc(mp *m, x unsafe.Pointer, size uintptr, skip int) {
 	c := getMCache(mp)
 	if c == nil {
 		throw("profilealloc called without a P or outside bootstrapping")
 	}
 	c.nextSample = nextSample()
-	mProf_Malloc(x, size)
+	mProf_Malloc(x, size, skip)
 }
 
 // nextSample returns the next sampling point for heap profiling. The goal is
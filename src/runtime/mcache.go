@@ -45,6 +45,31 @@ type mcache struct {
 
 	stackcache [_NumStackOrders]stackfreelist
 
+	// ifaceAllocs is the number of heap allocations performed by
+	// the P that owns this mcache to box values converted to
+	// interfaces. Like tinyAllocs, it's flushed to heapStats.
+	ifaceAllocs uintptr
+
+	// fastAllocs and slowAllocs are the number of small object
+	// allocations by the P that owns this mcache that were satisfied
+	// by nextFreeFast and that had to fall back to nextFree,
+	// respectively. Like tinyAllocs, they're flushed to heapStats.
+	fastAllocs uintptr
+	slowAllocs uintptr
+
+	// tinyBlocks is the number of new blocks the P that owns this
+	// mcache allocated for the tiny allocator, each on behalf of a tiny
+	// allocation that didn't fit in the current block. Unlike tinyAllocs,
+	// which doesn't include those allocations, it's flushed with the
+	// other counters above.
+	tinyBlocks uintptr
+
+	// largestAlloc is the size in bytes of the largest large object
+	// allocation by the P that owns this mcache. It's a high-water mark,
+	// so unlike the counters above it's never flushed, and it's read
+	// by other Ps, so it's only written atomically. See largestAlloc.
+	largestAlloc uintptr
+
 	// flushGen indicates the sweepgen during which this mcache
 	// was last flushed. If flushGen != mheap_.sweepgen, the spans
 	// in this mcache are stale and need to the flushed so they
@@ -103,6 +128,9 @@ func allocmcache() *mcache {
 // resources, such as statistics, so donate them to
 // a different mcache (the recipient).
 func freemcache(c *mcache) {
+	if c.largestAlloc > freedLargestAlloc {
+		freedLargestAlloc = c.largestAlloc
+	}
 	systemstack(func() {
 		c.releaseAll()
 		stackcache_clear(c)
@@ -167,6 +195,16 @@ func (c *mcache) refill(spc spanClass) {
 			atomic.Xadd64(&stats.tinyAllocCount, int64(c.tinyAllocs))
 			c.tinyAllocs = 0
 		}
+
+		// Flush ifaceAllocs, fastAllocs, and slowAllocs.
+		atomic.Xadd64(&stats.ifaceAllocCount, int64(c.ifaceAllocs))
+		c.ifaceAllocs = 0
+		atomic.Xadd64(&stats.fastAllocCount, int64(c.fastAllocs))
+		c.fastAllocs = 0
+		atomic.Xadd64(&stats.slowAllocCount, int64(c.slowAllocs))
+		c.slowAllocs = 0
+		atomic.Xadd64(&stats.tinyBlockCount, int64(c.tinyBlocks))
+		c.tinyBlocks = 0
 		memstats.heapStats.release()
 
 		// Count the allocs in inconsistent, internal stats.
@@ -201,6 +239,27 @@ func (c *mcache) refill(spc spanClass) {
 	c.alloc[spc] = s
 }
 
+// freedLargestAlloc is the largest of the largestAlloc fields of the
+// mcaches freed so far. Since mcaches are only freed with the world
+// stopped, it's only written with the world stopped.
+var freedLargestAlloc uintptr
+
+// largestAlloc returns the size in bytes of the largest large object
+// allocation requested so far by any P.
+func largestAlloc() uintptr {
+	// Stay non-preemptible so that the world can't stop and free
+	// mcaches while we're reading allp.
+	mp := acquirem()
+	largest := freedLargestAlloc
+	for _, p := range allp {
+		if n := atomic.Loaduintptr(&p.mcache.largestAlloc); n > largest {
+			largest = n
+		}
+	}
+	releasem(mp)
+	return largest
+}
+
 // allocLarge allocates a span for a large object.
 func (c *mcache) allocLarge(size uintptr, noscan bool) *mspan {
 	if size+_PageSize < size {
@@ -231,6 +290,11 @@ func (c *mcache) allocLarge(size uintptr, noscan bool) *mspan {
 	// Count the alloc in inconsistent, internal stats.
 	gcController.totalAlloc.Add(int64(npages * pageSize))
 
+	// Update this P's high-water mark for allocation size.
+	if size > c.largestAlloc {
+		atomic.Storeuintptr(&c.largestAlloc, size)
+	}
+
 	// Update heapLive.
 	gcController.update(int64(s.npages*pageSize), 0)
 
@@ -271,10 +335,18 @@ func (c *mcache) releaseAll() {
 	c.tiny = 0
 	c.tinyoffset = 0
 
-	// Flush tinyAllocs.
+	// Flush tinyAllocs, ifaceAllocs, fastAllocs, slowAllocs, and tinyBlocks.
 	stats := memstats.heapStats.acquire()
 	atomic.Xadd64(&stats.tinyAllocCount, int64(c.tinyAllocs))
 	c.tinyAllocs = 0
+	atomic.Xadd64(&stats.ifaceAllocCount, int64(c.ifaceAllocs))
+	c.ifaceAllocs = 0
+	atomic.Xadd64(&stats.fastAllocCount, int64(c.fastAllocs))
+	c.fastAllocs = 0
+	atomic.Xadd64(&stats.slowAllocCount, int64(c.slowAllocs))
+	c.slowAllocs = 0
+	atomic.Xadd64(&stats.tinyBlockCount, int64(c.tinyBlocks))
+	c.tinyBlocks = 0
 	memstats.heapStats.release()
 
 	// Updated heapScan.
@@ -4,7 +4,10 @@
 
 package runtime
 
-import "unsafe"
+import (
+	"runtime/internal/atomic"
+	"unsafe"
+)
 
 // OS memory management abstraction layer
 //
@@ -35,6 +38,11 @@ import "unsafe"
 // OS-specific implementations that handle errors, while the interface boundary
 // implements cross-OS functionality, like updating runtime accounting.
 
+// sysGrowthCalls is the number of calls to sysReserve and sysMap, that is,
+// the number of times the runtime has grown its address space or
+// prepared more of it for use.
+var sysGrowthCalls atomic.Uint64
+
 // sysAlloc transitions an OS-chosen region of memory from None to Ready.
 // More specifically, it obtains a large chunk of zeroed memory from the
 // operating system, typically on the order of a hundred kilobytes
@@ -128,6 +136,7 @@ func sysFault(v unsafe.Pointer, n uintptr) {
 // may use larger alignment, so the caller must be careful to realign the
 // memory obtained by sysReserve.
 func sysReserve(v unsafe.Pointer, n uintptr) unsafe.Pointer {
+	sysGrowthCalls.Add(1)
 	return sysReserveOS(v, n)
 }
 
@@ -136,6 +145,7 @@ func sysReserve(v unsafe.Pointer, n uintptr) unsafe.Pointer {
 //
 // sysStat must be non-nil.
 func sysMap(v unsafe.Pointer, n uintptr, sysStat *sysMemStat) {
+	sysGrowthCalls.Add(1)
 	sysStat.add(int64(n))
 	sysMapOS(v, n)
 }
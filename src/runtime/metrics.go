@@ -8,6 +8,7 @@ package runtime
 
 import (
 	"runtime/internal/atomic"
+	"runtime/internal/sys"
 	"unsafe"
 )
 
@@ -21,6 +22,7 @@ var (
 
 	sizeClassBuckets []float64
 	timeHistBuckets  []float64
+	stackSizeBuckets []float64
 )
 
 type metricData struct {
@@ -64,7 +66,95 @@ func initMetrics() {
 	sizeClassBuckets = append(sizeClassBuckets, float64Inf())
 
 	timeHistBuckets = timeHistogramMetricsBuckets()
+
+	// Goroutine stacks are always a power-of-two multiple of
+	// _FixedStack in size, so give each possible size its own
+	// bucket, up to 1 GiB, which is the default maximum stack size
+	// on 64-bit platforms.
+	stackSizeBuckets = append(stackSizeBuckets, 0)
+	for size := uint64(_FixedStack); size <= 1<<30; size <<= 1 {
+		stackSizeBuckets = append(stackSizeBuckets, float64(size))
+	}
+	stackSizeBuckets = append(stackSizeBuckets, float64Inf())
+
 	metrics = map[string]metricData{
+		"/build/version:string": {
+			compute: func(_ *statAggregate, out *metricValue) {
+				// String values are represented as a pointer to an
+				// immutable string.
+				out.kind = metricKindString
+				out.pointer = unsafe.Pointer(&buildVersion)
+			},
+		},
+		"/cpu/classes/gc/mark/assist:cpu-seconds": {
+			deps: makeStatDepSet(cpuStatsDep),
+			compute: func(in *statAggregate, out *metricValue) {
+				out.kind = metricKindFloat64
+				out.scalar = float64bits(float64(in.cpuStats.gcAssistTime) / 1e9)
+			},
+		},
+		"/cpu/classes/gc/mark/dedicated:cpu-seconds": {
+			deps: makeStatDepSet(cpuStatsDep),
+			compute: func(in *statAggregate, out *metricValue) {
+				out.kind = metricKindFloat64
+				out.scalar = float64bits(float64(in.cpuStats.gcDedicatedMarkTime) / 1e9)
+			},
+		},
+		"/cpu/classes/gc/mark/idle:cpu-seconds": {
+			deps: makeStatDepSet(cpuStatsDep),
+			compute: func(in *statAggregate, out *metricValue) {
+				out.kind = metricKindFloat64
+				out.scalar = float64bits(float64(in.cpuStats.gcIdleMarkTime) / 1e9)
+			},
+		},
+		"/cpu/classes/gc/pause:cpu-seconds": {
+			deps: makeStatDepSet(cpuStatsDep),
+			compute: func(in *statAggregate, out *metricValue) {
+				out.kind = metricKindFloat64
+				out.scalar = float64bits(float64(in.cpuStats.gcPauseTime) / 1e9)
+			},
+		},
+		"/cpu/classes/idle:cpu-seconds": {
+			deps: makeStatDepSet(cpuStatsDep),
+			compute: func(in *statAggregate, out *metricValue) {
+				out.kind = metricKindFloat64
+				out.scalar = float64bits(float64(in.cpuStats.idleTime) / 1e9)
+			},
+		},
+		"/cpu/classes/scavenge/total:cpu-seconds": {
+			deps: makeStatDepSet(cpuStatsDep),
+			compute: func(in *statAggregate, out *metricValue) {
+				out.kind = metricKindFloat64
+				out.scalar = float64bits(float64(in.cpuStats.scavengeTime) / 1e9)
+			},
+		},
+		"/cpu/classes/total:cpu-seconds": {
+			deps: makeStatDepSet(cpuStatsDep),
+			compute: func(in *statAggregate, out *metricValue) {
+				out.kind = metricKindFloat64
+				out.scalar = float64bits(float64(in.cpuStats.totalTime) / 1e9)
+			},
+		},
+		"/cpu/classes/user:cpu-seconds": {
+			deps: makeStatDepSet(cpuStatsDep),
+			compute: func(in *statAggregate, out *metricValue) {
+				out.kind = metricKindFloat64
+				out.scalar = float64bits(float64(in.cpuStats.userTime) / 1e9)
+			},
+		},
+		"/gc/assist/time:seconds": {
+			deps: makeStatDepSet(sysStatsDep),
+			compute: func(in *statAggregate, out *metricValue) {
+				out.kind = metricKindFloat64
+				out.scalar = float64bits(float64(in.sysStats.gcAssistTime) / 1e9)
+			},
+		},
+		"/gc/assist/wait:seconds": {
+			compute: func(_ *statAggregate, out *metricValue) {
+				out.kind = metricKindFloat64
+				out.scalar = float64bits(float64(memstats.gcAssistWaitTime.Load()) / 1e9)
+			},
+		},
 		"/gc/cycles/automatic:gc-cycles": {
 			deps: makeStatDepSet(sysStatsDep),
 			compute: func(in *statAggregate, out *metricValue) {
@@ -72,6 +162,15 @@ func initMetrics() {
 				out.scalar = in.sysStats.gcCyclesDone - in.sysStats.gcCyclesForced
 			},
 		},
+		"/gc/cycles/duration:seconds": {
+			compute: func(_ *statAggregate, out *metricValue) {
+				hist := out.float64HistOrInit(timeHistBuckets)
+				hist.counts[0] = atomic.Load64(&memstats.gcCycleDist.underflow)
+				for i := range memstats.gcCycleDist.counts {
+					hist.counts[i+1] = atomic.Load64(&memstats.gcCycleDist.counts[i])
+				}
+			},
+		},
 		"/gc/cycles/forced:gc-cycles": {
 			deps: makeStatDepSet(sysStatsDep),
 			compute: func(in *statAggregate, out *metricValue) {
@@ -86,6 +185,24 @@ func initMetrics() {
 				out.scalar = in.sysStats.gcCyclesDone
 			},
 		},
+		"/gc/finalizers/retained:objects": {
+			compute: func(_ *statAggregate, out *metricValue) {
+				out.kind = metricKindUint64
+				out.scalar = sweep.lastCycleFinRetained.Load()
+			},
+		},
+		"/gc/gogc:percent": {
+			compute: func(_ *statAggregate, out *metricValue) {
+				out.kind = metricKindUint64
+				out.scalar = uint64(int64(gcController.gcPercent.Load()))
+			},
+		},
+		"/gc/gomemlimit/exceeded:events": {
+			compute: func(_ *statAggregate, out *metricValue) {
+				out.kind = metricKindUint64
+				out.scalar = gcController.memoryLimitExceeded.Load()
+			},
+		},
 		"/gc/heap/allocs-by-size:bytes": {
 			deps: makeStatDepSet(heapStatsDep),
 			compute: func(in *statAggregate, out *metricValue) {
@@ -98,6 +215,46 @@ func initMetrics() {
 				}
 			},
 		},
+		"/gc/heap/allocs/fast-path:objects": {
+			deps: makeStatDepSet(heapStatsDep),
+			compute: func(in *statAggregate, out *metricValue) {
+				out.kind = metricKindUint64
+				out.scalar = in.heapStats.fastAllocCount
+			},
+		},
+		"/gc/heap/allocs/large:objects": {
+			deps: makeStatDepSet(heapStatsDep),
+			compute: func(in *statAggregate, out *metricValue) {
+				out.kind = metricKindUint64
+				out.scalar = in.heapStats.largeAllocCount
+			},
+		},
+		"/gc/heap/allocs/largest:bytes": {
+			compute: func(_ *statAggregate, out *metricValue) {
+				out.kind = metricKindUint64
+				out.scalar = uint64(largestAlloc())
+			},
+		},
+		"/gc/heap/allocs/mean-size:bytes": {
+			compute: func(_ *statAggregate, out *metricValue) {
+				out.kind = metricKindFloat64
+				out.scalar = memstats.cycleAllocMean.Load()
+			},
+		},
+		"/gc/heap/allocs/reason/interface:objects": {
+			deps: makeStatDepSet(heapStatsDep),
+			compute: func(in *statAggregate, out *metricValue) {
+				out.kind = metricKindUint64
+				out.scalar = in.heapStats.ifaceAllocCount
+			},
+		},
+		"/gc/heap/allocs/slow-path:objects": {
+			deps: makeStatDepSet(heapStatsDep),
+			compute: func(in *statAggregate, out *metricValue) {
+				out.kind = metricKindUint64
+				out.scalar = in.heapStats.slowAllocCount
+			},
+		},
 		"/gc/heap/allocs:bytes": {
 			deps: makeStatDepSet(heapStatsDep),
 			compute: func(in *statAggregate, out *metricValue) {
@@ -124,6 +281,19 @@ func initMetrics() {
 				}
 			},
 		},
+		"/gc/heap/frees-to-freelist:bytes": {
+			deps: makeStatDepSet(heapStatsDep),
+			compute: func(in *statAggregate, out *metricValue) {
+				out.kind = metricKindUint64
+				out.scalar = in.heapStats.totalFreed - in.heapStats.largeFree
+			},
+		},
+		"/gc/heap/frees/last-cycle:objects": {
+			compute: func(_ *statAggregate, out *metricValue) {
+				out.kind = metricKindUint64
+				out.scalar = sweep.lastCycleFreed.Load()
+			},
+		},
 		"/gc/heap/frees:bytes": {
 			deps: makeStatDepSet(heapStatsDep),
 			compute: func(in *statAggregate, out *metricValue) {
@@ -145,6 +315,12 @@ func initMetrics() {
 				out.scalar = in.sysStats.heapGoal
 			},
 		},
+		"/gc/heap/next-trigger:bytes": {
+			compute: func(_ *statAggregate, out *metricValue) {
+				out.kind = metricKindUint64
+				out.scalar, _ = gcController.trigger()
+			},
+		},
 		"/gc/heap/objects:objects": {
 			deps: makeStatDepSet(heapStatsDep),
 			compute: func(in *statAggregate, out *metricValue) {
@@ -152,6 +328,25 @@ func initMetrics() {
 				out.scalar = in.heapStats.numObjects
 			},
 		},
+		"/gc/heap/scannable:bytes": {
+			compute: func(_ *statAggregate, out *metricValue) {
+				out.kind = metricKindUint64
+				out.scalar = atomic.Load64(&gcController.heapScan)
+			},
+		},
+		"/gc/heap/size-classes/active:classes": {
+			deps: makeStatDepSet(heapStatsDep),
+			compute: func(in *statAggregate, out *metricValue) {
+				out.kind = metricKindUint64
+				out.scalar = 0
+				// Size class 0 is for large objects, which are tracked separately.
+				for i := 1; i < _NumSizeClasses; i++ {
+					if in.heapStats.smallAllocCount[i] > in.heapStats.smallFreeCount[i] {
+						out.scalar++
+					}
+				}
+			},
+		},
 		"/gc/heap/tiny/allocs:objects": {
 			deps: makeStatDepSet(heapStatsDep),
 			compute: func(in *statAggregate, out *metricValue) {
@@ -159,6 +354,88 @@ func initMetrics() {
 				out.scalar = uint64(in.heapStats.tinyAllocCount)
 			},
 		},
+		"/gc/heap/tiny/objects:objects": {
+			deps: makeStatDepSet(heapStatsDep),
+			compute: func(in *statAggregate, out *metricValue) {
+				out.kind = metricKindUint64
+				out.scalar = uint64(in.heapStats.tinyAllocCount + in.heapStats.tinyBlockCount)
+			},
+		},
+		"/gc/last-cycle/age:seconds": {
+			compute: func(_ *statAggregate, out *metricValue) {
+				last := int64(atomic.Load64(&memstats.last_gc_nanotime))
+				if last == 0 {
+					last = runtimeInitTime
+				}
+				age := nanotime() - last
+				if age < 0 {
+					// Guard against small amounts of clock skew
+					// between CPUs.
+					age = 0
+				}
+				out.kind = metricKindFloat64
+				out.scalar = float64bits(float64(age) / 1e9)
+			},
+		},
+		"/gc/mark/duration:seconds": {
+			compute: func(_ *statAggregate, out *metricValue) {
+				hist := out.float64HistOrInit(timeHistBuckets)
+				hist.counts[0] = atomic.Load64(&memstats.gcMarkDist.underflow)
+				for i := range memstats.gcMarkDist.counts {
+					hist.counts[i+1] = atomic.Load64(&memstats.gcMarkDist.counts[i])
+				}
+			},
+		},
+		"/gc/mark/workers:goroutines": {
+			compute: func(_ *statAggregate, out *metricValue) {
+				// The mode of each P is read without synchronization, like
+				// the free G lists in gcount, so the result may be inconsistent.
+				var n uint64
+				for _, pp := range allp {
+					if pp.gcMarkWorkerMode != gcMarkWorkerNotWorker {
+						n++
+					}
+				}
+				out.kind = metricKindUint64
+				out.scalar = n
+			},
+		},
+		"/gc/pacer/alloc-rate:bytes/second": {
+			compute: func(_ *statAggregate, out *metricValue) {
+				out.kind = metricKindFloat64
+				out.scalar = gcController.allocRate.Load()
+			},
+		},
+		"/gc/pacer/delays:events": {
+			compute: func(_ *statAggregate, out *metricValue) {
+				out.kind = metricKindUint64
+				out.scalar = gcController.delays.Load()
+			},
+		},
+		"/gc/pacer/trigger-ratio:ratio": {
+			compute: func(_ *statAggregate, out *metricValue) {
+				out.kind = metricKindFloat64
+				out.scalar = float64bits(gcController.triggerRatio())
+			},
+		},
+		"/gc/pauses/mark-termination:seconds": {
+			compute: func(_ *statAggregate, out *metricValue) {
+				hist := out.float64HistOrInit(timeHistBuckets)
+				hist.counts[0] = atomic.Load64(&memstats.gcMarkTermPauseDist.underflow)
+				for i := range memstats.gcMarkTermPauseDist.counts {
+					hist.counts[i+1] = atomic.Load64(&memstats.gcMarkTermPauseDist.counts[i])
+				}
+			},
+		},
+		"/gc/pauses/sweep-termination:seconds": {
+			compute: func(_ *statAggregate, out *metricValue) {
+				hist := out.float64HistOrInit(timeHistBuckets)
+				hist.counts[0] = atomic.Load64(&memstats.gcSweepTermPauseDist.underflow)
+				for i := range memstats.gcSweepTermPauseDist.counts {
+					hist.counts[i+1] = atomic.Load64(&memstats.gcSweepTermPauseDist.counts[i])
+				}
+			},
+		},
 		"/gc/pauses:seconds": {
 			compute: func(_ *statAggregate, out *metricValue) {
 				hist := out.float64HistOrInit(timeHistBuckets)
@@ -171,12 +448,50 @@ func initMetrics() {
 				}
 			},
 		},
+		"/gc/scan/total:bytes": {
+			deps: makeStatDepSet(sysStatsDep),
+			compute: func(in *statAggregate, out *metricValue) {
+				out.kind = metricKindUint64
+				out.scalar = uint64(in.sysStats.gcScanWork)
+			},
+		},
+		"/gc/scavenge/released:bytes": {
+			deps: makeStatDepSet(heapStatsDep),
+			compute: func(in *statAggregate, out *metricValue) {
+				out.kind = metricKindUint64
+				out.scalar = in.heapStats.scavenged
+			},
+		},
+		"/gc/stack/growths:operations": {
+			compute: func(_ *statAggregate, out *metricValue) {
+				out.kind = metricKindUint64
+				out.scalar = stackGrowths.Load()
+			},
+		},
+		"/gc/stack/shrinks:operations": {
+			compute: func(_ *statAggregate, out *metricValue) {
+				out.kind = metricKindUint64
+				out.scalar = stackShrinks.Load()
+			},
+		},
 		"/gc/stack/starting-size:bytes": {
 			compute: func(in *statAggregate, out *metricValue) {
 				out.kind = metricKindUint64
 				out.scalar = uint64(startingStackSize)
 			},
 		},
+		"/gc/sweep/spans:spans": {
+			compute: func(_ *statAggregate, out *metricValue) {
+				out.kind = metricKindUint64
+				out.scalar = sweep.spansSwept.Load()
+			},
+		},
+		"/gc/write-barrier/shades:operations": {
+			compute: func(_ *statAggregate, out *metricValue) {
+				out.kind = metricKindUint64
+				out.scalar = wbShades.Load()
+			},
+		},
 		"/memory/classes/heap/free:bytes": {
 			deps: makeStatDepSet(heapStatsDep),
 			compute: func(in *statAggregate, out *metricValue) {
@@ -249,6 +564,13 @@ func initMetrics() {
 				out.scalar = uint64(in.heapStats.inWorkBufs+in.heapStats.inPtrScalarBits) + in.sysStats.gcMiscSys
 			},
 		},
+		"/memory/classes/metadata/types:bytes": {
+			deps: makeStatDepSet(sysStatsDep),
+			compute: func(in *statAggregate, out *metricValue) {
+				out.kind = metricKindUint64
+				out.scalar = in.sysStats.itabSys
+			},
+		},
 		"/memory/classes/os-stacks:bytes": {
 			deps: makeStatDepSet(sysStatsDep),
 			compute: func(in *statAggregate, out *metricValue) {
@@ -277,7 +599,181 @@ func initMetrics() {
 				out.scalar = uint64(in.heapStats.committed+in.heapStats.released) +
 					in.sysStats.stacksSys + in.sysStats.mSpanSys +
 					in.sysStats.mCacheSys + in.sysStats.buckHashSys +
-					in.sysStats.gcMiscSys + in.sysStats.otherSys
+					in.sysStats.gcMiscSys + in.sysStats.itabSys +
+					in.sysStats.otherSys
+			},
+		},
+		"/memory/heap/arenas/reserved:bytes": {
+			deps: makeStatDepSet(sysStatsDep),
+			compute: func(in *statAggregate, out *metricValue) {
+				out.kind = metricKindUint64
+				out.scalar = in.sysStats.arenaReserved
+			},
+		},
+		"/memory/heap/arenas:objects": {
+			deps: makeStatDepSet(sysStatsDep),
+			compute: func(in *statAggregate, out *metricValue) {
+				out.kind = metricKindUint64
+				out.scalar = in.sysStats.heapArenas
+			},
+		},
+		"/memory/heap/chunks:objects": {
+			deps: makeStatDepSet(sysStatsDep),
+			compute: func(in *statAggregate, out *metricValue) {
+				out.kind = metricKindUint64
+				out.scalar = in.sysStats.pageChunks
+			},
+		},
+		"/memory/metadata/mcache/count:objects": {
+			deps: makeStatDepSet(sysStatsDep),
+			compute: func(in *statAggregate, out *metricValue) {
+				out.kind = metricKindUint64
+				out.scalar = in.sysStats.mCacheCount
+			},
+		},
+		"/memory/metadata/mspan/count:objects": {
+			deps: makeStatDepSet(sysStatsDep),
+			compute: func(in *statAggregate, out *metricValue) {
+				out.kind = metricKindUint64
+				out.scalar = in.sysStats.mSpanCount
+			},
+		},
+		"/memory/os-stacks:objects": {
+			compute: func(_ *statAggregate, out *metricValue) {
+				// The main thread always runs on an OS stack. Other threads
+				// only do if their g0 stack comes from the OS or the C
+				// toolchain; see allocm.
+				n := uint64(1)
+				if iscgo || mStackIsSystemAllocated() {
+					lock(&sched.lock)
+					n = uint64(mcount())
+					unlock(&sched.lock)
+				}
+				out.kind = metricKindUint64
+				out.scalar = n
+			},
+		},
+		"/memory/page-cache/hits:events": {
+			compute: func(_ *statAggregate, out *metricValue) {
+				out.kind = metricKindUint64
+				out.scalar = pageCacheHits.Load()
+			},
+		},
+		"/memory/page-cache/misses:events": {
+			compute: func(_ *statAggregate, out *metricValue) {
+				out.kind = metricKindUint64
+				out.scalar = pageCacheMisses.Load()
+			},
+		},
+		"/memory/scavenge/assist-ratio:ratio": {
+			compute: func(_ *statAggregate, out *metricValue) {
+				out.kind = metricKindFloat64
+				assist := mheap_.pages.scav.assistReleased.Load()
+				total := assist + mheap_.pages.scav.bgReleased.Load()
+				if total == 0 {
+					out.scalar = float64bits(0)
+					return
+				}
+				out.scalar = float64bits(float64(assist) / float64(total))
+			},
+		},
+		"/memory/scavenge/cpu-utilization:ratio": {
+			compute: func(_ *statAggregate, out *metricValue) {
+				out.kind = metricKindFloat64
+				lock(&scavenger.lock)
+				out.scalar = float64bits(scavenger.cpuUtilization)
+				unlock(&scavenger.lock)
+			},
+		},
+		"/memory/scavenge/refaults:faults": {
+			deps: makeStatDepSet(heapStatsDep),
+			compute: func(in *statAggregate, out *metricValue) {
+				out.kind = metricKindUint64
+				out.scalar = in.heapStats.recommitted / uint64(physPageSize)
+			},
+		},
+		"/memory/sys/mmap-calls:calls": {
+			compute: func(_ *statAggregate, out *metricValue) {
+				out.kind = metricKindUint64
+				out.scalar = sysGrowthCalls.Load()
+			},
+		},
+		"/memory/unaccounted:bytes": {
+			compute: func(_ *statAggregate, out *metricValue) {
+				out.kind = metricKindUint64
+				out.scalar = 0
+				for _, md := range activeModules() {
+					out.scalar += uint64(md.edata-md.data) + uint64(md.ebss-md.bss) +
+						uint64(md.enoptrdata-md.noptrdata) + uint64(md.enoptrbss-md.noptrbss)
+				}
+			},
+		},
+		"/process/uptime:seconds": {
+			compute: func(_ *statAggregate, out *metricValue) {
+				out.kind = metricKindFloat64
+				out.scalar = float64bits(float64(nanotime()-runtimeInitTime) / 1e9)
+			},
+		},
+		"/profiling/heap/records:records": {
+			compute: func(_ *statAggregate, out *metricValue) {
+				out.kind = metricKindUint64
+				out.scalar = mProfBuckets.Load()
+			},
+		},
+		"/sched/defers/pooled:objects": {
+			compute: func(_ *statAggregate, out *metricValue) {
+				out.kind = metricKindUint64
+				out.scalar = deferPoolLen()
+			},
+		},
+		"/sched/gomaxprocs/changes:events": {
+			compute: func(_ *statAggregate, out *metricValue) {
+				out.kind = metricKindUint64
+				out.scalar = sched.gomaxprocsChanges.Load()
+			},
+		},
+		"/sched/goroutines/created:goroutines": {
+			compute: func(_ *statAggregate, out *metricValue) {
+				out.kind = metricKindUint64
+				out.scalar = sched.goroutinesCreated.Load()
+			},
+		},
+		"/sched/goroutines/in-syscall:goroutines": {
+			compute: func(_ *statAggregate, out *metricValue) {
+				var n uint64
+				lock(&sched.lock)
+				for mp := allm; mp != nil; mp = mp.alllink {
+					if gp := mp.curg; gp != nil && readgstatus(gp) == _Gsyscall {
+						n++
+					}
+				}
+				unlock(&sched.lock)
+				out.kind = metricKindUint64
+				out.scalar = n
+			},
+		},
+		"/sched/goroutines/stack-size:bytes": {
+			compute: func(_ *statAggregate, out *metricValue) {
+				hist := out.float64HistOrInit(stackSizeBuckets)
+				for i := range hist.counts {
+					hist.counts[i] = 0
+				}
+				forEachG(func(gp *g) {
+					if readgstatus(gp) == _Gdead || isSystemGoroutine(gp, false) {
+						return
+					}
+					// Bucket 0 is [0, _FixedStack) and bucket i > 0 is
+					// [_FixedStack<<(i-1), _FixedStack<<i).
+					size := uint64(gp.stack.hi - gp.stack.lo)
+					bucket := 0
+					if size >= _FixedStack {
+						bucket = sys.Len64(size / _FixedStack)
+					}
+					if bucket >= len(hist.counts) {
+						bucket = len(hist.counts) - 1
+					}
+					hist.counts[bucket]++
+				})
 			},
 		},
 		"/sched/goroutines:goroutines": {
@@ -295,6 +791,95 @@ func initMetrics() {
 				}
 			},
 		},
+		"/sched/preemptions:events": {
+			compute: func(_ *statAggregate, out *metricValue) {
+				out.kind = metricKindUint64
+				out.scalar = sched.preemptions.Load()
+			},
+		},
+		"/sched/procs/idle-transitions:events": {
+			compute: func(_ *statAggregate, out *metricValue) {
+				out.kind = metricKindUint64
+				out.scalar = sched.pidleTransitions.Load()
+			},
+		},
+		"/sched/steal/attempts:events": {
+			compute: func(_ *statAggregate, out *metricValue) {
+				out.kind = metricKindUint64
+				out.scalar = sched.stealAttempts.Load()
+			},
+		},
+		"/sched/steal/successes:events": {
+			compute: func(_ *statAggregate, out *metricValue) {
+				out.kind = metricKindUint64
+				out.scalar = sched.stealSuccesses.Load()
+			},
+		},
+		"/sched/stw/events:events": {
+			compute: func(_ *statAggregate, out *metricValue) {
+				out.kind = metricKindUint64
+				out.scalar = sched.stwCount.Load()
+			},
+		},
+		"/sched/stw/total:seconds": {
+			compute: func(_ *statAggregate, out *metricValue) {
+				out.kind = metricKindFloat64
+				out.scalar = float64bits(float64(sched.stwTotalTime.Load()) / 1e9)
+			},
+		},
+		"/sched/threads/in-cgo:threads": {
+			compute: func(_ *statAggregate, out *metricValue) {
+				var n uint64
+				lock(&sched.lock)
+				for mp := allm; mp != nil; mp = mp.alllink {
+					if mp.incgo {
+						n++
+					}
+				}
+				unlock(&sched.lock)
+				out.kind = metricKindUint64
+				out.scalar = n
+			},
+		},
+		"/sched/threads/parked:threads": {
+			compute: func(_ *statAggregate, out *metricValue) {
+				lock(&sched.lock)
+				n := sched.nmidle
+				unlock(&sched.lock)
+				out.kind = metricKindUint64
+				out.scalar = uint64(n)
+			},
+		},
+		"/sched/threads/spinning:threads": {
+			compute: func(_ *statAggregate, out *metricValue) {
+				out.kind = metricKindUint64
+				out.scalar = uint64(atomic.Load(&sched.nmspinning))
+			},
+		},
+		"/sync/channels/block-time:seconds": {
+			compute: func(_ *statAggregate, out *metricValue) {
+				out.kind = metricKindFloat64
+				out.scalar = float64bits(float64(totalChanBlockTime()) / 1e9)
+			},
+		},
+		"/sync/mcentral/contentions:events": {
+			compute: func(_ *statAggregate, out *metricValue) {
+				out.kind = metricKindUint64
+				out.scalar = spanSetContentions.Load()
+			},
+		},
+		"/sync/pool/drained:objects": {
+			compute: func(_ *statAggregate, out *metricValue) {
+				out.kind = metricKindUint64
+				out.scalar = poolDrained.Load()
+			},
+		},
+		"/sync/runtime-locks/contentions:events": {
+			compute: func(_ *statAggregate, out *metricValue) {
+				out.kind = metricKindUint64
+				out.scalar = totalLockContentions()
+			},
+		},
 	}
 	metricsInit = true
 }
@@ -306,6 +891,7 @@ type statDep uint
 const (
 	heapStatsDep statDep = iota // corresponds to heapStatsAggregate
 	sysStatsDep                 // corresponds to sysStatsAggregate
+	cpuStatsDep                 // corresponds to cpuStatsAggregate
 	numStatsDeps
 )
 
@@ -421,14 +1007,22 @@ type sysStatsAggregate struct {
 	stacksSys      uint64
 	mSpanSys       uint64
 	mSpanInUse     uint64
+	mSpanCount     uint64
 	mCacheSys      uint64
 	mCacheInUse    uint64
+	mCacheCount    uint64
+	arenaReserved  uint64
+	heapArenas     uint64
+	pageChunks     uint64
 	buckHashSys    uint64
 	gcMiscSys      uint64
+	itabSys        uint64
 	otherSys       uint64
 	heapGoal       uint64
 	gcCyclesDone   uint64
 	gcCyclesForced uint64
+	gcAssistTime   int64
+	gcScanWork     int64
 }
 
 // compute populates the sysStatsAggregate with values from the runtime.
@@ -436,21 +1030,47 @@ func (a *sysStatsAggregate) compute() {
 	a.stacksSys = memstats.stacks_sys.load()
 	a.buckHashSys = memstats.buckhash_sys.load()
 	a.gcMiscSys = memstats.gcMiscSys.load()
+	a.itabSys = memstats.itabSys.load()
 	a.otherSys = memstats.other_sys.load()
 	a.heapGoal = gcController.heapGoal()
 	a.gcCyclesDone = uint64(memstats.numgc)
 	a.gcCyclesForced = uint64(memstats.numforcedgc)
+	a.gcAssistTime = memstats.gcAssistTime.Load()
+	a.gcScanWork = memstats.gcScanWork.Load()
 
 	systemstack(func() {
 		lock(&mheap_.lock)
 		a.mSpanSys = memstats.mspan_sys.load()
 		a.mSpanInUse = uint64(mheap_.spanalloc.inuse)
+		a.mSpanCount = uint64(mheap_.spanalloc.inuse / mheap_.spanalloc.size)
 		a.mCacheSys = memstats.mcache_sys.load()
 		a.mCacheInUse = uint64(mheap_.cachealloc.inuse)
+		a.mCacheCount = uint64(mheap_.cachealloc.inuse / mheap_.cachealloc.size)
+		// Heap arenas are reserved whole. On 32-bit platforms, the
+		// runtime also holds a reservation for future arenas.
+		a.heapArenas = uint64(len(mheap_.allArenas))
+		a.arenaReserved = a.heapArenas*heapArenaBytes +
+			uint64(mheap_.arena.end-mheap_.arena.next)
+		// The page allocator only ever grows by whole chunks.
+		a.pageChunks = uint64(mheap_.pages.inUse.totalBytes / pallocChunkBytes)
 		unlock(&mheap_.lock)
 	})
 }
 
+// cpuStatsAggregate represents CPU time stats obtained from the
+// runtime. They're grouped together because they're derived from one
+// another, so they must all be read at once to be consistent.
+type cpuStatsAggregate struct {
+	cpuStats
+}
+
+// compute populates the cpuStatsAggregate with values from the runtime.
+func (a *cpuStatsAggregate) compute() {
+	lock(&sched.lock)
+	a.cpuStats = readCPUStats(nanotime())
+	unlock(&sched.lock)
+}
+
 // statAggregate is the main driver of the metrics implementation.
 //
 // It contains multiple aggregates of runtime statistics, as well
@@ -460,6 +1080,7 @@ type statAggregate struct {
 	ensured   statDepSet
 	heapStats heapStatsAggregate
 	sysStats  sysStatsAggregate
+	cpuStats  cpuStatsAggregate
 }
 
 // ensure populates statistics aggregates determined by deps if they
@@ -478,6 +1099,8 @@ func (a *statAggregate) ensure(deps *statDepSet) {
 			a.heapStats.compute()
 		case sysStatsDep:
 			a.sysStats.compute()
+		case cpuStatsDep:
+			a.cpuStats.compute()
 		}
 	}
 	a.ensured = a.ensured.union(missing)
@@ -494,6 +1117,7 @@ const (
 	metricKindUint64
 	metricKindFloat64
 	metricKindFloat64Histogram
+	metricKindString
 )
 
 // metricSample is a runtime copy of runtime/metrics.Sample and
@@ -514,6 +1138,12 @@ type metricValue struct {
 // float64HistOrInit tries to pull out an existing float64Histogram
 // from the value, but if none exists, then it allocates one with
 // the given buckets.
+//
+// An existing histogram's counts slice is reused if it already has
+// the right length, so that reading the same []metrics.Sample over and
+// over doesn't allocate. runtime/metrics.Read documents this behavior,
+// so every histogram metric must go through here, and callers must
+// overwrite every element of counts.
 func (v *metricValue) float64HistOrInit(buckets []float64) *metricFloat64Histogram {
 	var hist *metricFloat64Histogram
 	if v.kind == metricKindFloat64Histogram && v.pointer != nil {
@@ -0,0 +1,74 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package metrics
+
+import (
+	"io"
+	"math"
+	"strconv"
+)
+
+// WriteCSV reads all supported metrics and writes the scalar ones to w in
+// CSV format, one row per metric, preceded by the header row
+//
+//	name,value,unit,cumulative
+//
+// The unit is taken from the metric's name, and cumulative is "true" or
+// "false". Histogram metrics and metrics that are unsupported on the current
+// platform are omitted. String values are quoted if necessary.
+func WriteCSV(w io.Writer) error {
+	samples := allSamples()
+	Read(samples)
+
+	buf := []byte("name,value,unit,cumulative\n")
+	for i, s := range samples {
+		switch s.Value.kind {
+		case KindUint64:
+			buf = append(buf, s.Name...)
+			buf = append(buf, ',')
+			buf = strconv.AppendUint(buf, s.Value.scalar, 10)
+		case KindFloat64:
+			buf = append(buf, s.Name...)
+			buf = append(buf, ',')
+			buf = strconv.AppendFloat(buf, math.Float64frombits(s.Value.scalar), 'g', -1, 64)
+		case KindString:
+			buf = append(buf, s.Name...)
+			buf = append(buf, ',')
+			buf = appendCSVField(buf, s.Value.StringValue())
+		default:
+			continue
+		}
+		buf = append(buf, ',')
+		buf = append(buf, allDesc[i].Unit...)
+		buf = append(buf, ',')
+		buf = strconv.AppendBool(buf, allDesc[i].Cumulative)
+		buf = append(buf, '\n')
+	}
+	_, err := w.Write(buf)
+	return err
+}
+
+// appendCSVField appends s to buf as a CSV field, quoting it
+// as described in RFC 4180 if it contains special characters.
+func appendCSVField(buf []byte, s string) []byte {
+	quote := false
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; c == ',' || c == '"' || c == '\n' || c == '\r' {
+			quote = true
+			break
+		}
+	}
+	if !quote {
+		return append(buf, s...)
+	}
+	buf = append(buf, '"')
+	for i := 0; i < len(s); i++ {
+		if s[i] == '"' {
+			buf = append(buf, '"')
+		}
+		buf = append(buf, s[i])
+	}
+	return append(buf, '"')
+}
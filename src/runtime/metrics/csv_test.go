@@ -0,0 +1,56 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package metrics_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"reflect"
+	"runtime/metrics"
+	"strconv"
+	"testing"
+)
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := metrics.WriteCSV(&buf); err != nil {
+		t.Fatalf("WriteCSV failed: %v", err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("WriteCSV produced invalid CSV: %v", err)
+	}
+	if len(records) == 0 {
+		t.Fatal("WriteCSV produced no output")
+	}
+	if got, want := records[0], []string{"name", "value", "unit", "cumulative"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("WriteCSV header is %q, want %q", got, want)
+	}
+
+	kinds := make(map[string]metrics.ValueKind)
+	for _, d := range metrics.All() {
+		kinds[d.Name] = d.Kind
+	}
+	found := false
+	for _, r := range records[1:] {
+		if k := kinds[r[0]]; k == metrics.KindFloat64Histogram || k == metrics.KindBad {
+			t.Errorf("WriteCSV wrote a row for %s of kind %v", r[0], k)
+		}
+		if r[0] != "/sched/goroutines:goroutines" {
+			continue
+		}
+		found = true
+		n, err := strconv.ParseUint(r[1], 10, 64)
+		if err != nil || n == 0 {
+			t.Errorf("WriteCSV wrote goroutine count %q, want a positive integer", r[1])
+		}
+		if r[2] != "goroutines" || r[3] != "false" {
+			t.Errorf("WriteCSV wrote unit %q and cumulative %q for goroutine count, want \"goroutines\" and \"false\"", r[2], r[3])
+		}
+	}
+	if !found {
+		t.Error("WriteCSV did not write /sched/goroutines:goroutines")
+	}
+}
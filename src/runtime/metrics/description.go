@@ -4,6 +4,8 @@
 
 package metrics
 
+import "sort"
+
 // Description describes a runtime metric.
 type Description struct {
 	// Name is the full name of the metric which includes the unit.
@@ -31,6 +33,13 @@ type Description struct {
 	// A complete name might look like "/memory/heap/free:bytes".
 	Name string
 
+	// Unit is the unit component of Name, for example "bytes" for
+	// "/memory/heap/free:bytes".
+	//
+	// It is derived from Name, which remains authoritative, and is provided
+	// so that users need not parse it out of Name themselves.
+	Unit string
+
 	// Description is an English language sentence describing the metric.
 	Description string
 
@@ -51,12 +60,114 @@ type Description struct {
 // The English language descriptions below must be kept in sync with the
 // descriptions of each metric in doc.go.
 var allDesc = []Description{
+	{
+		Name:        "/build/version:string",
+		Description: "The Go version the program was built with, as reported by runtime.Version.",
+		Kind:        KindString,
+	},
+	{
+		Name: "/cpu/classes/gc/mark/assist:cpu-seconds",
+		Description: "Estimated total CPU time goroutines spent performing GC tasks to assist the " +
+			"GC and prevent it from falling behind the application. Updated at the end of " +
+			"each GC cycle, and the same value as /gc/assist/time:seconds.",
+		Kind:       KindFloat64,
+		Cumulative: true,
+	},
+	{
+		Name: "/cpu/classes/gc/mark/dedicated:cpu-seconds",
+		Description: "Estimated total CPU time spent performing GC tasks on processors (as defined " +
+			"by GOMAXPROCS) dedicated, in whole or in part, to those tasks. Updated at " +
+			"the end of each GC cycle.",
+		Kind:       KindFloat64,
+		Cumulative: true,
+	},
+	{
+		Name: "/cpu/classes/gc/mark/idle:cpu-seconds",
+		Description: "Estimated total CPU time spent performing GC tasks on spare CPU resources " +
+			"that the Go scheduler could not otherwise find a use for. Updated at the end " +
+			"of each GC cycle.",
+		Kind:       KindFloat64,
+		Cumulative: true,
+	},
+	{
+		Name: "/cpu/classes/gc/pause:cpu-seconds",
+		Description: "Estimated total CPU time spent with the application paused by the GC. Even " +
+			"if only one thread is running during the pause, this is computed as " +
+			"GOMAXPROCS times the pause latency because nothing else can be executing. " +
+			"Updated at the end of each GC cycle.",
+		Kind:       KindFloat64,
+		Cumulative: true,
+	},
+	{
+		Name: "/cpu/classes/idle:cpu-seconds",
+		Description: "Estimated total available CPU time during which the runtime's Ps (of which " +
+			"there are GOMAXPROCS) had no work to do and sat idle.",
+		Kind:       KindFloat64,
+		Cumulative: true,
+	},
+	{
+		Name: "/cpu/classes/scavenge/total:cpu-seconds",
+		Description: "Estimated total CPU time spent returning unused memory to the underlying " +
+			"platform, both by the background scavenger and by goroutines that had to " +
+			"release memory while allocating in order to stay within the memory limit. " +
+			"This is an overestimate if the scavenging goroutine is descheduled while it " +
+			"works.",
+		Kind:       KindFloat64,
+		Cumulative: true,
+	},
+	{
+		Name: "/cpu/classes/total:cpu-seconds",
+		Description: "Estimated total CPU time available to the runtime, which is GOMAXPROCS " +
+			"multiplied by the wall time the program has been running, accounting for any " +
+			"changes to GOMAXPROCS. The other /cpu/classes metrics sum to this value, and " +
+			"like them, it is not updated while a GC cycle is in progress.",
+		Kind:       KindFloat64,
+		Cumulative: true,
+	},
+	{
+		Name: "/cpu/classes/user:cpu-seconds",
+		Description: "Estimated total CPU time spent running application code, that is, " +
+			"/cpu/classes/total:cpu-seconds less the time in all the other /cpu/classes " +
+			"metrics.",
+		Kind:       KindFloat64,
+		Cumulative: true,
+	},
+	{
+		Name: "/gc/assist/time:seconds",
+		Description: "Estimated total CPU time goroutines spent performing GC assists, updated at " +
+			"the end of each GC cycle. Assists steal time from the application when the " +
+			"GC falls behind allocation, so high values indicate that the GC is not " +
+			"keeping up.",
+		Kind:       KindFloat64,
+		Cumulative: true,
+	},
+	{
+		Name: "/gc/assist/wait:seconds",
+		Description: "Cumulative wall-clock time goroutines have spent blocked waiting for GC " +
+			"assist credit. A goroutine that owes the GC assist work but finds no marking " +
+			"work left to do parks until background mark workers earn enough credit to " +
+			"pay off its debt. Unlike /gc/assist/time:seconds, which is time spent doing " +
+			"assist work, this is time spent doing nothing, and it is added as soon as " +
+			"each goroutine resumes rather than at the end of the GC cycle.",
+		Kind:       KindFloat64,
+		Cumulative: true,
+	},
 	{
 		Name:        "/gc/cycles/automatic:gc-cycles",
 		Description: "Count of completed GC cycles generated by the Go runtime.",
 		Kind:        KindUint64,
 		Cumulative:  true,
 	},
+	{
+		Name: "/gc/cycles/duration:seconds",
+		Description: "Distribution of the wall-clock durations of completed GC cycles, from the " +
+			"start of sweep termination to the end of mark termination. Unlike " +
+			"/gc/pauses:seconds, which only covers the stop-the-world pauses, this " +
+			"includes the concurrent mark phase, during which the application continues " +
+			"to run.",
+		Kind:       KindFloat64Histogram,
+		Cumulative: true,
+	},
 	{
 		Name:        "/gc/cycles/forced:gc-cycles",
 		Description: "Count of completed GC cycles forced by the application.",
@@ -69,6 +180,31 @@ var allDesc = []Description{
 		Kind:        KindUint64,
 		Cumulative:  true,
 	},
+	{
+		Name: "/gc/finalizers/retained:objects",
+		Description: "Estimated number of heap objects that the last completed GC cycle found " +
+			"unreachable but kept alive to run their finalizers. Objects they reference, " +
+			"which are also kept alive, are not counted.",
+		Kind: KindUint64,
+	},
+	{
+		Name: "/gc/gogc:percent",
+		Description: "Heap size target percentage, as set by the GOGC environment variable or " +
+			"debug.SetGCPercent, otherwise 100. If the GC is disabled, as with GOGC=off, " +
+			"the value is the maximum uint64 value, which is -1 when converted to int64, " +
+			"matching the value debug.SetGCPercent uses to mean off.",
+		Kind: KindUint64,
+	},
+	{
+		Name: "/gc/gomemlimit/exceeded:events",
+		Description: "Cumulative count of GC cycles that ended with the live heap, by itself, " +
+			"larger than the soft memory limit set by GOMEMLIMIT or debug.SetMemoryLimit. " +
+			"On those occasions the limit cannot be honored no matter how often the GC " +
+			"runs, so a non-zero and increasing value means the program is using more " +
+			"memory than the limit allows and is at high risk of running out of memory.",
+		Kind:       KindUint64,
+		Cumulative: true,
+	},
 	{
 		Name: "/gc/heap/allocs-by-size:bytes",
 		Description: "Distribution of heap allocations by approximate size. " +
@@ -77,6 +213,63 @@ var allDesc = []Description{
 		Kind:       KindFloat64Histogram,
 		Cumulative: true,
 	},
+	{
+		Name: "/gc/heap/allocs/fast-path:objects",
+		Description: "Cumulative count of small heap allocations taken directly from the next free " +
+			"slot of the span cached by the allocating P, the allocator's fastest path. " +
+			"Large allocations, and tiny allocations combined into an existing block, are " +
+			"counted in neither this metric nor /gc/heap/allocs/slow-path:objects. This " +
+			"count is updated lazily by each P.",
+		Kind:       KindUint64,
+		Cumulative: true,
+	},
+	{
+		Name: "/gc/heap/allocs/large:objects",
+		Description: "Cumulative count of heap allocations too large for any size class, which are " +
+			"those of more than 32 KiB. Each such allocation gets its own span directly " +
+			"from the page allocator, so a high rate puts pressure on the page allocator " +
+			"rather than the per-P caches. The threshold is the size of the largest size " +
+			"class, which is currently 32 KiB on all platforms but may change between " +
+			"releases.",
+		Kind:       KindUint64,
+		Cumulative: true,
+	},
+	{
+		Name: "/gc/heap/allocs/largest:bytes",
+		Description: "Size of the largest single heap allocation requested since the program " +
+			"started. Only allocations too large for any size class (see " +
+			"/gc/heap/allocs-by-size:bytes) are tracked, so this is zero if every " +
+			"allocation so far was small. It is never reset.",
+		Kind: KindUint64,
+	},
+	{
+		Name: "/gc/heap/allocs/mean-size:bytes",
+		Description: "Mean size of the heap objects allocated during the most recently completed " +
+			"GC cycle, that is, between the ends of the two most recent cycles. This is a " +
+			"per-cycle value, not a smoothed one, and it is zero until the first GC cycle " +
+			"completes.",
+		Kind: KindFloat64,
+	},
+	{
+		Name: "/gc/heap/allocs/reason/interface:objects",
+		Description: "Cumulative count of heap allocations made to box values converted to " +
+			"interfaces. This count is updated lazily by each P, so it may lag slightly " +
+			"behind. Closures are not counted separately, since the compiler allocates " +
+			"their contexts like any other value that escapes to the heap.",
+		Kind:       KindUint64,
+		Cumulative: true,
+	},
+	{
+		Name: "/gc/heap/allocs/slow-path:objects",
+		Description: "Cumulative count of small heap allocations that could not be satisfied by " +
+			"the fast path counted in /gc/heap/allocs/fast-path:objects, and instead had " +
+			"to search the cached span's allocation bitmap or refill the P's cache with a " +
+			"new span, which may involve sweeping or obtaining memory from the page " +
+			"allocator. A rising ratio of slow-path to fast-path allocations means more " +
+			"allocations are paying these costs. This count is updated lazily by each P.",
+		Kind:       KindUint64,
+		Cumulative: true,
+	},
 	{
 		Name:        "/gc/heap/allocs:bytes",
 		Description: "Cumulative sum of memory allocated to the heap by the application.",
@@ -99,6 +292,26 @@ var allDesc = []Description{
 		Kind:       KindFloat64Histogram,
 		Cumulative: true,
 	},
+	{
+		Name: "/gc/heap/frees-to-freelist:bytes",
+		Description: "Cumulative sum of memory freed by the garbage collector that was occupied by " +
+			"small objects, which sweeping returns to the free lists of their spans, so " +
+			"it can be reused for new objects of the same size class without involving " +
+			"the page allocator or the underlying system. Memory freed by large objects, " +
+			"which is returned to the page heap instead, is the rest of " +
+			"/gc/heap/frees:bytes. Unlike /gc/scavenge/released:bytes, which is memory " +
+			"returned to the underlying system, this memory remains mapped and part of " +
+			"the heap.",
+		Kind:       KindUint64,
+		Cumulative: true,
+	},
+	{
+		Name: "/gc/heap/frees/last-cycle:objects",
+		Description: "Count of heap objects freed by the sweep phase of the most recently " +
+			"completed GC cycle. Unlike /gc/heap/frees:objects, this is not cumulative: " +
+			"it is replaced with a new count each time a cycle's sweep phase completes.",
+		Kind: KindUint64,
+	},
 	{
 		Name:        "/gc/heap/frees:bytes",
 		Description: "Cumulative sum of heap memory freed by the garbage collector.",
@@ -119,11 +332,36 @@ var allDesc = []Description{
 		Description: "Heap size target for the end of the GC cycle.",
 		Kind:        KindUint64,
 	},
+	{
+		Name: "/gc/heap/next-trigger:bytes",
+		Description: "Heap size, in the same terms as /gc/heap/goal:bytes, at which the GC pacer " +
+			"currently intends to start the next GC cycle. The pacer recomputes it as the " +
+			"program runs, so it may move in either direction between cycles.",
+		Kind: KindUint64,
+	},
 	{
 		Name:        "/gc/heap/objects:objects",
 		Description: "Number of objects, live or unswept, occupying heap memory.",
 		Kind:        KindUint64,
 	},
+	{
+		Name: "/gc/heap/scannable:bytes",
+		Description: "Estimated bytes of heap memory that contain pointers and so must be scanned " +
+			"by the GC, as used by the GC pacer. This is the scannable portion of the " +
+			"heap that survived the last GC cycle plus the scannable portion of heap " +
+			"memory allocated since, excluding pointer-free objects and the pointer-free " +
+			"tails of objects, so it is at most the size of the heap in use by objects.",
+		Kind: KindUint64,
+	},
+	{
+		Name: "/gc/heap/size-classes/active:classes",
+		Description: "Number of size classes that hold at least one live heap object, or more " +
+			"precisely, at least one object that has not yet been swept as freed. Each " +
+			"small allocation is rounded up to one of a fixed set of size classes, so " +
+			"this reflects the diversity of the sizes of small objects the program keeps " +
+			"alive. Large objects do not belong to any size class and are not counted.",
+		Kind: KindUint64,
+	},
 	{
 		Name: "/gc/heap/tiny/allocs:objects",
 		Description: "Count of small allocations that are packed together into blocks. " +
@@ -134,18 +372,160 @@ var allDesc = []Description{
 		Kind:       KindUint64,
 		Cumulative: true,
 	},
+	{
+		Name: "/gc/heap/tiny/objects:objects",
+		Description: "Count of all allocations served by the tiny allocator, which packs small " +
+			"allocations without pointers together into 16-byte blocks. This includes " +
+			"both the allocations packed into an existing block, which are counted by " +
+			"/gc/heap/tiny/allocs:objects, and the allocations that started a new block, " +
+			"which are counted, as blocks, in /gc/heap/allocs-by-size:bytes. The " +
+			"difference between this metric and /gc/heap/tiny/allocs:objects is therefore " +
+			"the number of blocks the tiny allocator has allocated.",
+		Kind:       KindUint64,
+		Cumulative: true,
+	},
+	{
+		Name: "/gc/last-cycle/age:seconds",
+		Description: "Time elapsed since the end of the most recently completed GC cycle, computed " +
+			"when the metric is read. This value resets toward zero each time a GC cycle " +
+			"completes. If no GC cycle has completed yet, it is the time since the " +
+			"program started.",
+		Kind: KindFloat64,
+	},
+	{
+		Name: "/gc/mark/duration:seconds",
+		Description: "Distribution of the wall-clock durations of the concurrent mark phase of " +
+			"completed GC cycles, from the end of the sweep termination pause to the " +
+			"start of the mark termination pause that ends the cycle. Unlike " +
+			"/gc/pauses:seconds, this is time during which goroutines keep running " +
+			"alongside the GC. It includes any brief pauses for mark termination attempts " +
+			"that had to resume concurrent marking, and together with the sweep and mark " +
+			"termination pauses makes up /gc/cycles/duration:seconds.",
+		Kind:       KindFloat64Histogram,
+		Cumulative: true,
+	},
+	{
+		Name: "/gc/mark/workers:goroutines",
+		Description: "Number of GC mark worker goroutines currently running, whether dedicated, " +
+			"fractional, or idle workers. This is usually zero between GC cycles and at " +
+			"most GOMAXPROCS during the mark phase, when it varies as workers start and " +
+			"stop.",
+		Kind: KindUint64,
+	},
+	{
+		Name: "/gc/pacer/alloc-rate:bytes/second",
+		Description: "Estimated rate at which the application allocates heap memory. This is a " +
+			"smoothed estimate the runtime maintains by averaging the rate observed over " +
+			"each GC cycle with its previous estimate, updated at the end of each cycle, " +
+			"not an instantaneous measurement. Zero until the first GC cycle completes.",
+		Kind: KindFloat64,
+	},
+	{
+		Name: "/gc/pacer/delays:events",
+		Description: "Count of GC cycles, started by heap growth, whose start the GC pacer delayed " +
+			"past the point its runway estimate called for, in order to keep the trigger " +
+			"within its bounds. This happens most often when the live heap is small or " +
+			"close to the heap goal.",
+		Kind:       KindUint64,
+		Cumulative: true,
+	},
+	{
+		Name: "/gc/pacer/trigger-ratio:ratio",
+		Description: "Heap growth, as a fraction of the heap marked live by the last GC cycle, at " +
+			"which the GC pacer will start the next cycle. This is the effective trigger, " +
+			"which is typically somewhat below GOGC/100 so that the GC finishes before " +
+			"reaching its heap goal, and may be much lower when the memory limit binds, " +
+			"or higher for small heaps. Zero until the first GC cycle completes.",
+		Kind: KindFloat64,
+	},
+	{
+		Name: "/gc/pauses/mark-termination:seconds",
+		Description: "Distribution of the stop-the-world pause latencies for GC mark termination, " +
+			"which ends marking and each GC cycle, including attempts at mark termination " +
+			"that find more marking work and resume concurrent marking. Every pause in " +
+			"/gc/pauses:seconds is counted in either this metric or " +
+			"/gc/pauses/sweep-termination:seconds, so their combined counts equal its " +
+			"count.",
+		Kind:       KindFloat64Histogram,
+		Cumulative: true,
+	},
+	{
+		Name: "/gc/pauses/sweep-termination:seconds",
+		Description: "Distribution of the stop-the-world pause latencies for GC sweep termination, " +
+			"which starts each GC cycle and enables the write barrier before concurrent " +
+			"marking begins. Every pause in /gc/pauses:seconds is counted in either this " +
+			"metric or /gc/pauses/mark-termination:seconds, so their combined counts " +
+			"equal its count.",
+		Kind:       KindFloat64Histogram,
+		Cumulative: true,
+	},
 	{
 		Name:        "/gc/pauses:seconds",
 		Description: "Distribution individual GC-related stop-the-world pause latencies.",
 		Kind:        KindFloat64Histogram,
 		Cumulative:  true,
 	},
+	{
+		Name: "/gc/scan/total:bytes",
+		Description: "Cumulative bytes of heap, goroutine stacks, and global variables scanned by " +
+			"the GC, updated at the end of each GC cycle. Dividing the change in this " +
+			"metric by the change in /gc/cycles/total:gc-cycles approximates the scan " +
+			"work performed per cycle.",
+		Kind:       KindUint64,
+		Cumulative: true,
+	},
+	{
+		Name: "/gc/scavenge/released:bytes",
+		Description: "Cumulative sum of memory returned to the underlying system by the scavenger. " +
+			"Unlike /memory/classes/heap/released:bytes, which is the amount of memory " +
+			"that is currently released, this value never decreases, even as released " +
+			"memory is reused.",
+		Kind:       KindUint64,
+		Cumulative: true,
+	},
+	{
+		Name: "/gc/stack/growths:operations",
+		Description: "Count of goroutine stacks grown because they ran out of space, by copying " +
+			"them to a larger stack. Paired with /gc/stack/shrinks:operations, this helps " +
+			"diagnose stack thrashing, where goroutine stacks repeatedly grow and shrink.",
+		Kind:       KindUint64,
+		Cumulative: true,
+	},
+	{
+		Name: "/gc/stack/shrinks:operations",
+		Description: "Count of goroutine stacks shrunk by the garbage collector. Paired with " +
+			"/gc/stack/starting-size:bytes, this helps diagnose stack thrashing, where " +
+			"goroutine stacks repeatedly grow and shrink.",
+		Kind:       KindUint64,
+		Cumulative: true,
+	},
 	{
 		Name:        "/gc/stack/starting-size:bytes",
 		Description: "The stack size of new goroutines.",
 		Kind:        KindUint64,
 		Cumulative:  false,
 	},
+	{
+		Name: "/gc/sweep/spans:spans",
+		Description: "Cumulative count of heap spans swept by the GC. Every span in use must be " +
+			"swept once per GC cycle before it can be reused, and sweeping happens in the " +
+			"background as well as when goroutines allocate, so bursts of sweeping " +
+			"typically follow the start of each cycle and are proportional to the size of " +
+			"the heap.",
+		Kind:       KindUint64,
+		Cumulative: true,
+	},
+	{
+		Name: "/gc/write-barrier/shades:operations",
+		Description: "Count of heap objects shaded, that is, marked so the GC will not free them, " +
+			"by the write barrier. The write barrier is only enabled while the GC is " +
+			"marking, so this only advances during the mark phase, at a rate that depends " +
+			"on how often goroutines write pointers to unmarked objects into the heap " +
+			"while the GC runs. Shades are counted in batches as each P's write barrier " +
+			"buffer is flushed, so the count may lag slightly.",
+		Kind:       KindUint64,
+		Cumulative: true,
+	},
 	{
 		Name: "/memory/classes/heap/free:bytes",
 		Description: "Memory that is completely free and eligible to be returned to the underlying system, " +
@@ -200,6 +580,13 @@ var allDesc = []Description{
 		Description: "Memory that is reserved for or used to hold runtime metadata.",
 		Kind:        KindUint64,
 	},
+	{
+		Name: "/memory/classes/metadata/types:bytes",
+		Description: "Memory that is reserved for or used to hold itabs the runtime builds while " +
+			"the program runs, for conversions to interface types that the compiler could " +
+			"not lay out ahead of time. Itabs are never freed.",
+		Kind: KindUint64,
+	},
 	{
 		Name:        "/memory/classes/os-stacks:bytes",
 		Description: "Stack memory allocated by the underlying operating system.",
@@ -220,6 +607,178 @@ var allDesc = []Description{
 		Description: "All memory mapped by the Go runtime into the current process as read-write. Note that this does not include memory mapped by code called via cgo or via the syscall package. Sum of all metrics in /memory/classes.",
 		Kind:        KindUint64,
 	},
+	{
+		Name: "/memory/heap/arenas/reserved:bytes",
+		Description: "Address space reserved by the runtime for heap arenas, whether or not it is " +
+			"currently mapped as read-write or backed by physical memory.",
+		Kind: KindUint64,
+	},
+	{
+		Name: "/memory/heap/arenas:objects",
+		Description: "Number of heap arenas the runtime has mapped. The heap is made up of arenas, " +
+			"each of which is 64 MiB of address space on most 64-bit platforms, and 4 MiB " +
+			"on Windows, WebAssembly, iOS, and 32-bit platforms, so multiplying by the " +
+			"arena size estimates the address space used by the heap. Arenas are never " +
+			"unmapped, so this value never decreases.",
+		Kind: KindUint64,
+	},
+	{
+		Name: "/memory/heap/chunks:objects",
+		Description: "Number of 4 MiB chunks of address space managed by the runtime's page " +
+			"allocator, which hands out the pages that make up the heap. Chunks are never " +
+			"released, so this value never decreases.",
+		Kind: KindUint64,
+	},
+	{
+		Name: "/memory/metadata/mcache/count:objects",
+		Description: "Number of runtime mcache structures currently allocated. Each P owns one " +
+			"mcache, so this normally equals GOMAXPROCS, though it may briefly differ " +
+			"while GOMAXPROCS is changing. Dividing " +
+			"/memory/classes/metadata/mcache/inuse:bytes by this count gives the size of " +
+			"a single mcache.",
+		Kind: KindUint64,
+	},
+	{
+		Name: "/memory/metadata/mspan/count:objects",
+		Description: "Number of runtime mspan structures currently allocated. " +
+			"Dividing /memory/classes/metadata/mspan/inuse:bytes by this count approximates the " +
+			"average size of span metadata.",
+		Kind: KindUint64,
+	},
+	{
+		Name: "/memory/os-stacks:objects",
+		Description: "Number of stacks allocated by the underlying operating system that are " +
+			"currently in use by the runtime. The main thread always runs on such a " +
+			"stack, as does every other runtime thread in programs that use cgo and on " +
+			"platforms where the operating system allocates thread stacks, so in those " +
+			"programs this count tracks the number of threads.",
+		Kind: KindUint64,
+	},
+	{
+		Name: "/memory/page-cache/hits:events",
+		Description: "Count of span allocations served from a P's page cache, a small per-P cache " +
+			"of free pages that lets allocations of up to 15 pages avoid taking the heap " +
+			"lock.",
+		Kind:       KindUint64,
+		Cumulative: true,
+	},
+	{
+		Name: "/memory/page-cache/misses:events",
+		Description: "Count of span allocations small enough for a P's page cache that it could " +
+			"not serve, either because it was empty and had to be refilled, or because it " +
+			"lacked enough contiguous free pages, so the heap lock had to be taken. A " +
+			"high ratio of misses to /memory/page-cache/hits:events indicates contention " +
+			"for the page allocator or fragmentation of the heap.",
+		Kind:       KindUint64,
+		Cumulative: true,
+	},
+	{
+		Name: "/memory/scavenge/assist-ratio:ratio",
+		Description: "Fraction of the memory returned to the operating system by the scavenger, " +
+			"over the lifetime of the program, that was returned by goroutines assisting " +
+			"the scavenger while allocating, rather than by the background scavenger. " +
+			"Allocations assist when the background scavenger can't keep the program's " +
+			"memory below the memory limit. A high value means that allocations are " +
+			"paying the cost of returning memory. Memory returned by debug.FreeOSMemory " +
+			"is not counted. Zero if no memory has been returned.",
+		Kind: KindFloat64,
+	},
+	{
+		Name: "/memory/scavenge/cpu-utilization:ratio",
+		Description: "CPU utilization of the background scavenger over its most recent period of " +
+			"work and sleep, as a fraction of its CPU cap, which is 1% of the total " +
+			"available CPU time (GOMAXPROCS). Ranges from 0 to 1, where 1 means the " +
+			"scavenger is running at its cap. Updated after each batch of scavenging " +
+			"work, and reset to zero when the scavenger has no more work to do.",
+		Kind: KindFloat64,
+	},
+	{
+		Name: "/memory/scavenge/refaults:faults",
+		Description: "Estimated count of page faults caused by reusing memory that had been " +
+			"returned to the underlying system. This is a runtime estimate, computed as " +
+			"the amount of such memory reused for allocation divided by the system page " +
+			"size, rather than a hardware or operating system counter. Memory newly " +
+			"obtained from the system is counted the same way, since it is also not " +
+			"backed by physical memory until first used.",
+		Kind:       KindUint64,
+		Cumulative: true,
+	},
+	{
+		Name: "/memory/sys/mmap-calls:calls",
+		Description: "Count of operations in which the runtime reserved more address space from " +
+			"the underlying system or mapped more of it for use. A count that keeps " +
+			"rising while the application is in a steady state may indicate that the heap " +
+			"is repeatedly growing and shrinking.",
+		Kind:       KindUint64,
+		Cumulative: true,
+	},
+	{
+		Name: "/memory/unaccounted:bytes",
+		Description: "Size of the data and BSS segments, which hold global variables, of the " +
+			"executable and of any Go plugins or shared libraries. This memory is mapped " +
+			"read-write, but is not part of /memory/classes/total:bytes.",
+		Kind: KindUint64,
+	},
+	{
+		Name: "/process/uptime:seconds",
+		Description: "Time elapsed since the Go runtime was initialized. It is measured with the " +
+			"same monotonic clock the runtime uses internally, so it is unaffected by " +
+			"adjustments to the system's wall clock.",
+		Kind: KindFloat64,
+	},
+	{
+		Name: "/profiling/heap/records:records",
+		Description: "Number of records in the heap profile, one per distinct combination of " +
+			"allocation call stack and object size among sampled allocations. Records are " +
+			"never removed, so this grows with the diversity of allocation sites in the " +
+			"program rather than with the size of the heap.",
+		Kind: KindUint64,
+	},
+	{
+		Name: "/sched/defers/pooled:objects",
+		Description: "Number of defer records currently cached in the runtime's free pools for " +
+			"reuse. Defers that cannot be allocated on the stack, such as those in loops, " +
+			"are drawn from these pools, which are emptied at the start of each GC cycle. " +
+			"This is an indicator of internal allocation health rather than of " +
+			"application behavior.",
+		Kind: KindUint64,
+	},
+	{
+		Name: "/sched/gomaxprocs/changes:events",
+		Description: "Cumulative count of changes to GOMAXPROCS made by runtime.GOMAXPROCS after " +
+			"the program started. Calls that set GOMAXPROCS to its current value do not " +
+			"count as changes.",
+		Kind:       KindUint64,
+		Cumulative: true,
+	},
+	{
+		Name: "/sched/goroutines/created:goroutines",
+		Description: "Cumulative count of goroutines created, not counting those the runtime " +
+			"starts for its own use. Together with /sched/goroutines:goroutines, which is " +
+			"the number currently live, the rate of change of this metric gives both the " +
+			"rate at which goroutines are created and the rate at which they exit.",
+		Kind:       KindUint64,
+		Cumulative: true,
+	},
+	{
+		Name: "/sched/goroutines/in-syscall:goroutines",
+		Description: "Number of goroutines currently executing or blocked in a system call, " +
+			"including calls into C code via cgo. Each such goroutine occupies an OS " +
+			"thread for the duration of the call, and the runtime hands that thread's P " +
+			"to another thread if the call blocks, so a high value explains why a program " +
+			"is running more OS threads than GOMAXPROCS. Goroutines blocked in network " +
+			"I/O and other operations handled by the runtime's network poller are not in " +
+			"a system call, and are not counted.",
+		Kind: KindUint64,
+	},
+	{
+		Name: "/sched/goroutines/stack-size:bytes",
+		Description: "Distribution of the stack sizes of all live goroutines, as counted by " +
+			"/sched/goroutines:goroutines. This is a point-in-time snapshot taken when " +
+			"the metric is read, and computing it requires visiting every goroutine, so " +
+			"it may be expensive in programs with millions of goroutines.",
+		Kind: KindFloat64Histogram,
+	},
 	{
 		Name:        "/sched/goroutines:goroutines",
 		Description: "Count of live goroutines.",
@@ -230,9 +789,210 @@ var allDesc = []Description{
 		Description: "Distribution of the time goroutines have spent in the scheduler in a runnable state before actually running.",
 		Kind:        KindFloat64Histogram,
 	},
+	{
+		Name: "/sched/preemptions:events",
+		Description: "Count of times a running goroutine was preempted, either by the scheduler to " +
+			"give other goroutines a turn, or to stop it for the GC. Preemption is " +
+			"cooperative when the goroutine notices the request at the stack check in a " +
+			"function prologue, and asynchronous when the runtime interrupts it with a " +
+			"signal, which is how goroutines in tight loops without function calls are " +
+			"preempted.",
+		Kind:       KindUint64,
+		Cumulative: true,
+	},
+	{
+		Name: "/sched/procs/idle-transitions:events",
+		Description: "Count of times a processor (as defined by GOMAXPROCS) ran out of work and " +
+			"became idle. Frequent idle transitions while the application is under load " +
+			"may indicate that work is poorly distributed across goroutines.",
+		Kind:       KindUint64,
+		Cumulative: true,
+	},
+	{
+		Name: "/sched/steal/attempts:events",
+		Description: "Cumulative count of attempts by an idle P to steal goroutines from the run " +
+			"queue of another P that isn't idle. See /sched/steal/successes:events.",
+		Kind:       KindUint64,
+		Cumulative: true,
+	},
+	{
+		Name: "/sched/steal/successes:events",
+		Description: "Cumulative count of attempts counted by /sched/steal/attempts:events that " +
+			"found at least one goroutine to steal. When the program is busy, a low ratio " +
+			"of successes to attempts suggests that work is poorly balanced across Ps, " +
+			"with idle Ps repeatedly finding nothing to take.",
+		Kind:       KindUint64,
+		Cumulative: true,
+	},
+	{
+		Name: "/sched/stw/events:events",
+		Description: "Cumulative count of stop-the-world events of all causes. Unlike " +
+			"/gc/pauses:seconds, which only includes GC-related pauses, this also " +
+			"includes stop-the-world events for other reasons, such as calls to " +
+			"runtime.ReadMemStats.",
+		Kind:       KindUint64,
+		Cumulative: true,
+	},
+	{
+		Name: "/sched/stw/total:seconds",
+		Description: "Cumulative time spent with the world stopped, for stop-the-world events of " +
+			"all causes. See /sched/stw/events:events.",
+		Kind:       KindFloat64,
+		Cumulative: true,
+	},
+	{
+		Name: "/sched/threads/in-cgo:threads",
+		Description: "Number of OS threads currently executing or blocked in C code called via " +
+			"cgo. Such threads do not count against GOMAXPROCS, so a high value relative " +
+			"to GOMAXPROCS explains why a program is running more OS threads than " +
+			"expected.",
+		Kind: KindUint64,
+	},
+	{
+		Name: "/sched/threads/parked:threads",
+		Description: "Number of OS threads currently parked with nothing to do, which the runtime " +
+			"keeps to reuse rather than exiting them.",
+		Kind: KindUint64,
+	},
+	{
+		Name: "/sched/threads/spinning:threads",
+		Description: "Number of OS threads currently spinning, that is, actively looking for " +
+			"goroutines to run rather than running one or sleeping. A persistently high " +
+			"value relative to GOMAXPROCS may indicate CPU time wasted on searching for " +
+			"work.",
+		Kind: KindUint64,
+	},
+	{
+		Name: "/sync/channels/block-time:seconds",
+		Description: "Estimated total time goroutines have spent blocked in channel sends, channel " +
+			"receives, and select statements, waiting for another goroutine to complete " +
+			"the operation. The time runs from when a goroutine blocks until it resumes " +
+			"running, so it includes any scheduling delay after it is woken. Operations " +
+			"that complete without blocking are not counted. Each time a goroutine blocks " +
+			"on a channel, measuring this costs two reads of the clock and an update to a " +
+			"total kept by the thread it resumes on, and reading the metric sums those " +
+			"totals over all threads.",
+		Kind:       KindFloat64,
+		Cumulative: true,
+	},
+	{
+		Name: "/sync/mcentral/contentions:events",
+		Description: "Count of times taking a span from a central span list, one of the " +
+			"per-size-class lists of spans that all Ps allocate from when their own " +
+			"caches run out, had to be retried because another P modified the list at the " +
+			"same time. The central lists are lock-free, so this is their equivalent of " +
+			"lock contention. High values when many cores are allocating objects of the " +
+			"same sizes indicate pressure on the central lists.",
+		Kind:       KindUint64,
+		Cumulative: true,
+	},
+	{
+		Name: "/sync/pool/drained:objects",
+		Description: "Cumulative count of objects the GC has dropped from sync.Pools. At the start " +
+			"of each GC cycle, objects that have sat in a pool since the previous cycle " +
+			"without being retrieved are discarded. A high rate of drained objects " +
+			"relative to the rate at which pools are used suggests that the pools aren't " +
+			"usefully retaining objects for reuse.",
+		Kind:       KindUint64,
+		Cumulative: true,
+	},
+	{
+		Name: "/sync/runtime-locks/contentions:events",
+		Description: "Cumulative count of times a runtime-internal lock was already held when the " +
+			"runtime tried to acquire it. Every internal runtime mutex is included, for " +
+			"example the scheduler lock, the heap lock, and the locks on channels, " +
+			"timers, and semaphore queues. Locks in the sync package, such as sync.Mutex, " +
+			"are not.",
+		Kind:       KindUint64,
+		Cumulative: true,
+	},
+}
+
+func init() {
+	for i := range allDesc {
+		allDesc[i].Unit = unitOf(allDesc[i].Name)
+	}
+}
+
+// unitOf returns the unit component of a metric name, which is
+// everything after the colon.
+func unitOf(name string) string {
+	for i := len(name) - 1; i >= 0; i-- {
+		if name[i] == ':' {
+			return name[i+1:]
+		}
+	}
+	return ""
 }
 
 // All returns a slice of containing metric descriptions for all supported metrics.
 func All() []Description {
 	return allDesc
 }
+
+// AllSorted returns a new slice containing metric descriptions for all
+// supported metrics, ordered lexicographically by Name.
+//
+// Unlike All, the returned slice may be freely modified by the caller.
+func AllSorted() []Description {
+	descs := make([]Description, len(allDesc))
+	copy(descs, allDesc)
+	sort.Slice(descs, func(i, j int) bool {
+		return descs[i].Name < descs[j].Name
+	})
+	return descs
+}
+
+// AllCumulative returns a new slice containing metric descriptions for all
+// supported metrics that are cumulative, in the same order as All.
+func AllCumulative() []Description {
+	var descs []Description
+	for _, d := range allDesc {
+		if d.Cumulative {
+			descs = append(descs, d)
+		}
+	}
+	return descs
+}
+
+// AllByUnit returns a new slice containing metric descriptions for all
+// supported metrics whose Unit is exactly unit, in the same order as All.
+// For example, "bytes" matches /gc/heap/allocs:bytes, but not
+// /gc/pacer/alloc-rate:bytes/second.
+//
+// If no metric has the unit, AllByUnit returns an empty, non-nil slice.
+func AllByUnit(unit string) []Description {
+	descs := []Description{}
+	for _, d := range allDesc {
+		if d.Unit == unit {
+			descs = append(descs, d)
+		}
+	}
+	return descs
+}
+
+// lookupDesc returns the description of the supported metric with
+// the given name, or nil if there is no such metric.
+func lookupDesc(name string) *Description {
+	i := sort.Search(len(allDesc), func(i int) bool {
+		return allDesc[i].Name >= name
+	})
+	if i < len(allDesc) && allDesc[i].Name == name {
+		return &allDesc[i]
+	}
+	return nil
+}
+
+// DescriptionMap returns a new map from the Name of each supported metric,
+// as returned by All, to its Description.
+//
+// The map is freshly allocated on each call, and holds copies of the
+// descriptions, so the caller may modify it freely without affecting All
+// or later calls to DescriptionMap.
+func DescriptionMap() map[string]Description {
+	descs := make(map[string]Description, len(allDesc))
+	for _, d := range allDesc {
+		descs[d.Name] = d
+	}
+	return descs
+}
@@ -7,14 +7,18 @@ package metrics_test
 import (
 	"bufio"
 	"os"
+	"reflect"
 	"regexp"
 	"runtime/metrics"
 	"strings"
 	"testing"
 )
 
+// nameFormat is the format of metric names documented on Description.Name.
+var nameFormat = regexp.MustCompile("^(?P<name>/[^:]+):(?P<unit>[^:*/]+(?:[*/][^:*/]+)*)$")
+
 func TestDescriptionNameFormat(t *testing.T) {
-	r := regexp.MustCompile("^(?P<name>/[^:]+):(?P<unit>[^:*/]+(?:[*/][^:*/]+)*)$")
+	r := nameFormat
 	descriptions := metrics.All()
 	for _, desc := range descriptions {
 		if !r.MatchString(desc.Name) {
@@ -23,6 +27,20 @@ func TestDescriptionNameFormat(t *testing.T) {
 	}
 }
 
+func TestDescriptionUnit(t *testing.T) {
+	r := nameFormat
+	for _, desc := range metrics.All() {
+		m := r.FindStringSubmatch(desc.Name)
+		if m == nil {
+			t.Errorf("metrics %q does not match regexp %s", desc.Name, r)
+			continue
+		}
+		if unit := m[r.SubexpIndex("unit")]; desc.Unit != unit {
+			t.Errorf("metric %q has Unit %q, want %q", desc.Name, desc.Unit, unit)
+		}
+	}
+}
+
 func extractMetricDocs(t *testing.T) map[string]string {
 	f, err := os.Open("doc.go")
 	if err != nil {
@@ -113,3 +131,135 @@ func TestDescriptionDocs(t *testing.T) {
 		}
 	}
 }
+
+func TestAllSorted(t *testing.T) {
+	all := metrics.All()
+	sorted := metrics.AllSorted()
+	if len(sorted) != len(all) {
+		t.Fatalf("AllSorted returned %d descriptions, want %d", len(sorted), len(all))
+	}
+	for i := 1; i < len(sorted); i++ {
+		if sorted[i-1].Name >= sorted[i].Name {
+			t.Errorf("metrics %s and %s are out of lexicographical order", sorted[i-1].Name, sorted[i].Name)
+		}
+	}
+	want := make(map[string]metrics.Description)
+	for _, d := range all {
+		want[d.Name] = d
+	}
+	for _, d := range sorted {
+		w, ok := want[d.Name]
+		if !ok {
+			t.Errorf("AllSorted returned unknown metric %s", d.Name)
+			continue
+		}
+		if d != w {
+			t.Errorf("AllSorted description for %s differs from All: got %+v, want %+v", d.Name, d, w)
+		}
+		delete(want, d.Name)
+	}
+	for name := range want {
+		t.Errorf("AllSorted is missing metric %s", name)
+	}
+
+	// Make sure AllSorted doesn't hand out the underlying table.
+	if len(sorted) > 0 {
+		sorted[0].Name = "/bad/metric:name"
+		for _, d := range metrics.All() {
+			if d.Name == sorted[0].Name {
+				t.Error("modifying the result of AllSorted modified All")
+			}
+		}
+	}
+}
+
+func TestAllCumulative(t *testing.T) {
+	var want []metrics.Description
+	for _, d := range metrics.All() {
+		if d.Cumulative {
+			want = append(want, d)
+		}
+	}
+	got := metrics.AllCumulative()
+	if len(got) != len(want) {
+		t.Fatalf("AllCumulative returned %d descriptions, want %d", len(got), len(want))
+	}
+	for i, d := range got {
+		if !d.Cumulative {
+			t.Errorf("AllCumulative returned non-cumulative metric %s", d.Name)
+		}
+		if d != want[i] {
+			t.Errorf("AllCumulative()[%d] = %s, want %s", i, d.Name, want[i].Name)
+		}
+	}
+}
+
+func TestDescriptionMap(t *testing.T) {
+	all := metrics.All()
+	m := metrics.DescriptionMap()
+	if len(m) != len(all) {
+		t.Errorf("DescriptionMap has %d entries, want %d", len(m), len(all))
+	}
+	for _, d := range all {
+		if got, ok := m[d.Name]; !ok || got != d {
+			t.Errorf("DescriptionMap[%q] = %+v, %v; want %+v", d.Name, got, ok, d)
+		}
+	}
+
+	// Modifying the map must not affect later results.
+	name := all[0].Name
+	d := m[name]
+	d.Description = "modified"
+	m[name] = d
+	delete(m, all[1].Name)
+	m2 := metrics.DescriptionMap()
+	if m2[name] != all[0] {
+		t.Errorf("DescriptionMap[%q] = %+v after modifying an earlier result, want %+v", name, m2[name], all[0])
+	}
+	if _, ok := m2[all[1].Name]; !ok {
+		t.Errorf("DescriptionMap lacks %q after deleting it from an earlier result", all[1].Name)
+	}
+}
+
+func TestAllByUnit(t *testing.T) {
+	names := func(descs []metrics.Description) map[string]bool {
+		m := make(map[string]bool)
+		for _, d := range descs {
+			m[d.Name] = true
+		}
+		return m
+	}
+
+	bytes := names(metrics.AllByUnit("bytes"))
+	for _, name := range []string{
+		"/memory/classes/heap/free:bytes",
+		"/memory/classes/total:bytes",
+		"/gc/heap/allocs:bytes",
+	} {
+		if !bytes[name] {
+			t.Errorf("AllByUnit(\"bytes\") lacks %s", name)
+		}
+	}
+	if bytes["/gc/pacer/alloc-rate:bytes/second"] {
+		t.Errorf("AllByUnit(\"bytes\") includes /gc/pacer/alloc-rate:bytes/second")
+	}
+
+	cycles := names(metrics.AllByUnit("gc-cycles"))
+	want := map[string]bool{
+		"/gc/cycles/automatic:gc-cycles": true,
+		"/gc/cycles/forced:gc-cycles":    true,
+		"/gc/cycles/total:gc-cycles":     true,
+	}
+	if !reflect.DeepEqual(cycles, want) {
+		t.Errorf("AllByUnit(\"gc-cycles\") = %v, want %v", cycles, want)
+	}
+
+	for _, d := range metrics.AllByUnit("bytes") {
+		if d.Unit != "bytes" {
+			t.Errorf("AllByUnit(\"bytes\") includes %s with unit %q", d.Name, d.Unit)
+		}
+	}
+	if got := metrics.AllByUnit("no-such-unit"); got == nil || len(got) != 0 {
+		t.Errorf("AllByUnit(\"no-such-unit\") = %#v, want an empty slice", got)
+	}
+}
@@ -64,6 +64,8 @@ func ExampleRead_readingAllMetrics() {
 			// The histogram may be quite large, so let's just pull out
 			// a crude estimate for the median for the sake of this example.
 			fmt.Printf("%s: %f\n", name, medianBucket(value.Float64Histogram()))
+		case metrics.KindString:
+			fmt.Printf("%s: %s\n", name, value.StringValue())
 		case metrics.KindBad:
 			// This should never happen because all metrics are supported
 			// by construction.
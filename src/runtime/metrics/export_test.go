@@ -0,0 +1,38 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package metrics
+
+import (
+	"math"
+	"unsafe"
+)
+
+// NewUint64Value returns a KindUint64 Value for v.
+func NewUint64Value(v uint64) Value {
+	return Value{kind: KindUint64, scalar: v}
+}
+
+// NewFloat64Value returns a KindFloat64 Value for v.
+func NewFloat64Value(v float64) Value {
+	return Value{kind: KindFloat64, scalar: math.Float64bits(v)}
+}
+
+var ValidName = validName
+
+var AppendPrometheus = appendPrometheus
+
+var AppendJSON = appendJSON
+
+// NewFloat64HistogramValue returns a KindFloat64Histogram Value for h.
+func NewFloat64HistogramValue(h *Float64Histogram) Value {
+	return Value{kind: KindFloat64Histogram, pointer: unsafe.Pointer(h)}
+}
+
+// ResetRegistered forgets every description added with Register.
+func ResetRegistered() {
+	registered.Lock()
+	registered.descs = nil
+	registered.Unlock()
+}
@@ -4,6 +4,12 @@
 
 package metrics
 
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
 // Float64Histogram represents a distribution of float64 values.
 type Float64Histogram struct {
 	// Counts contains the weights for each histogram bucket.
@@ -31,3 +37,261 @@ type Float64Histogram struct {
 	// modified, the user must make a copy.
 	Buckets []float64
 }
+
+// HistogramBucket is a single bucket of a Float64Histogram, paired
+// with its count.
+type HistogramBucket struct {
+	// Lower and Upper are the bucket's inclusive lower bound
+	// and exclusive upper bound.
+	Lower, Upper float64
+
+	// Count is the weight of the range [Lower, Upper).
+	Count uint64
+}
+
+var (
+	errBucketsMismatch = errors.New("runtime/metrics: histogram buckets do not match")
+	errCountsDecreased = errors.New("runtime/metrics: histogram counts decreased")
+	errBadBoundaries   = errors.New("runtime/metrics: boundaries are not increasing or do not cover histogram buckets")
+)
+
+// sameBuckets reports whether a and b describe identical bucket layouts.
+func sameBuckets(a, b []float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Add adds the counts of other into h, element-wise.
+//
+// h and other must have identical Buckets, otherwise Add returns an error
+// and h is left unmodified. If h has no Counts yet, Add allocates them, so
+// h may be a zero histogram that has only had its Buckets set.
+func (h *Float64Histogram) Add(other *Float64Histogram) error {
+	if !sameBuckets(h.Buckets, other.Buckets) {
+		return errBucketsMismatch
+	}
+	if h.Counts == nil && len(other.Counts) != 0 {
+		h.Counts = make([]uint64, len(other.Counts))
+	}
+	if len(h.Counts) != len(other.Counts) {
+		return errBucketsMismatch
+	}
+	for i, c := range other.Counts {
+		h.Counts[i] += c
+	}
+	return nil
+}
+
+// Sub returns a new histogram whose counts are the element-wise difference
+// between h and prev, which is typically an earlier sample of the same
+// cumulative metric. The result shares h's Buckets.
+//
+// Sub returns an error if h and prev do not have identical Buckets, or if
+// any count in prev exceeds the corresponding count in h, which indicates
+// that the underlying counter was reset between the two samples.
+func (h *Float64Histogram) Sub(prev *Float64Histogram) (*Float64Histogram, error) {
+	if !sameBuckets(h.Buckets, prev.Buckets) || len(h.Counts) != len(prev.Counts) {
+		return nil, errBucketsMismatch
+	}
+	delta := &Float64Histogram{
+		Counts:  make([]uint64, len(h.Counts)),
+		Buckets: h.Buckets,
+	}
+	for i, c := range h.Counts {
+		if prev.Counts[i] > c {
+			return nil, errCountsDecreased
+		}
+		delta.Counts[i] = c - prev.Counts[i]
+	}
+	return delta, nil
+}
+
+// CumulativeCounts returns a new slice of the same length as h.Counts whose
+// i'th element is the sum of h.Counts[0] through h.Counts[i], saturating at
+// the maximum uint64 value rather than overflowing. This is the form needed
+// to render a cumulative distribution function.
+func (h *Float64Histogram) CumulativeCounts() []uint64 {
+	cum := make([]uint64, len(h.Counts))
+	var total uint64
+	for i, c := range h.Counts {
+		if total+c < total {
+			total = ^uint64(0)
+		} else {
+			total += c
+		}
+		cum[i] = total
+	}
+	return cum
+}
+
+// Trim returns a new histogram without the runs of zero-count buckets at
+// either end of h, which are common in sparse distributions. The retained
+// buckets keep their boundaries, and the result's Buckets alias h's, so
+// the total count is unchanged.
+//
+// If every count in h is zero, Trim returns an empty histogram, with no
+// Counts and no Buckets.
+func (h *Float64Histogram) Trim() *Float64Histogram {
+	first, last := -1, -1
+	for i, c := range h.Counts {
+		if c == 0 {
+			continue
+		}
+		if first < 0 {
+			first = i
+		}
+		last = i
+	}
+	if first < 0 {
+		return &Float64Histogram{}
+	}
+	counts := make([]uint64, last+1-first)
+	copy(counts, h.Counts[first:last+1])
+	return &Float64Histogram{
+		Counts:  counts,
+		Buckets: h.Buckets[first : last+2],
+	}
+}
+
+// Bucketize returns a new slice with one HistogramBucket for each element of
+// h.Counts, in the same order, which is convenient for plotting.
+//
+// The bounds are copied from h.Buckets unchanged, so the first bucket's Lower
+// may be -Inf and the last bucket's Upper may be +Inf. Callers that can't
+// plot unbounded buckets must handle those edges themselves, for example by
+// clamping them to the neighboring finite bound.
+func (h *Float64Histogram) Bucketize() []HistogramBucket {
+	buckets := make([]HistogramBucket, len(h.Counts))
+	for i, c := range h.Counts {
+		buckets[i] = HistogramBucket{
+			Lower: h.Buckets[i],
+			Upper: h.Buckets[i+1],
+			Count: c,
+		}
+	}
+	return buckets
+}
+
+// ScaleCounts returns a new histogram with the same Buckets as h, whose
+// counts are h's multiplied by factor, for example to normalize histograms
+// sampled over different intervals before adding them.
+//
+// Each scaled count is rounded to the nearest integer, with halfway cases
+// rounded away from zero. Results too large for a uint64 saturate at the
+// maximum uint64 value, and negative results, as well as NaN, become zero.
+func (h *Float64Histogram) ScaleCounts(factor float64) *Float64Histogram {
+	scaled := &Float64Histogram{
+		Counts:  make([]uint64, len(h.Counts)),
+		Buckets: h.Buckets,
+	}
+	for i, c := range h.Counts {
+		v := math.Round(float64(c) * factor)
+		switch {
+		case v >= 1<<64:
+			scaled.Counts[i] = math.MaxUint64
+		case v > 0:
+			scaled.Counts[i] = uint64(v)
+		}
+	}
+	return scaled
+}
+
+// Downsample returns a new histogram with the buckets defined by boundaries,
+// typically fewer and wider ones than h's, into which h's counts have been
+// redistributed, for example to reduce the size of exported data.
+//
+// boundaries has the same form as Buckets: it must have at least two
+// elements, in strictly increasing order, which may start with -Inf and end
+// with +Inf. It must also cover all of h's buckets, that is, boundaries[0]
+// must be at most h.Buckets[0], and boundaries[len(boundaries)-1] must be at
+// least h.Buckets[len(h.Buckets)-1]. Otherwise, Downsample returns an error.
+//
+// Each of h's counts is assigned to the new bucket containing the lower
+// bound of its bucket, even if its bucket extends into the next new bucket,
+// so the total count is preserved. Choosing boundaries from among h.Buckets
+// avoids that imprecision. The result's Buckets is a copy of boundaries.
+func (h *Float64Histogram) Downsample(boundaries []float64) (*Float64Histogram, error) {
+	if len(boundaries) < 2 {
+		return nil, errBadBoundaries
+	}
+	for i := 1; i < len(boundaries); i++ {
+		if !(boundaries[i-1] < boundaries[i]) {
+			return nil, errBadBoundaries
+		}
+	}
+	if n := len(h.Buckets); n > 0 && (h.Buckets[0] < boundaries[0] || h.Buckets[n-1] > boundaries[len(boundaries)-1]) {
+		return nil, errBadBoundaries
+	}
+	down := &Float64Histogram{
+		Counts:  make([]uint64, len(boundaries)-1),
+		Buckets: append([]float64(nil), boundaries...),
+	}
+	j := 0
+	for i, c := range h.Counts {
+		for h.Buckets[i] >= boundaries[j+1] {
+			j++
+		}
+		down.Counts[j] += c
+	}
+	return down, nil
+}
+
+// MergeHistograms returns a new histogram holding the combined counts of hs,
+// which need not have the same Buckets, for example because they come from
+// different versions of Go.
+//
+// The result's Buckets is the sorted union of the Buckets of hs, so each
+// bucket of each input spans one or more consecutive buckets of the result.
+// Each input count is assigned to the first of those, the one containing the
+// lower bound of the input's bucket. The total count is thus preserved, but
+// where another input splits a bucket more finely, the merged distribution
+// is skewed towards the bucket's lower bound. Inputs with identical Buckets
+// merge exactly, as with Add.
+//
+// Nil histograms and histograms without Buckets are ignored. If no histogram
+// has any Buckets, MergeHistograms returns nil.
+func MergeHistograms(hs ...*Float64Histogram) *Float64Histogram {
+	var buckets []float64
+	for _, h := range hs {
+		if h != nil {
+			buckets = append(buckets, h.Buckets...)
+		}
+	}
+	if len(buckets) == 0 {
+		return nil
+	}
+	sort.Float64s(buckets)
+	n := 1
+	for _, b := range buckets[1:] {
+		if b != buckets[n-1] {
+			buckets[n] = b
+			n++
+		}
+	}
+	buckets = buckets[:n]
+
+	merged := &Float64Histogram{Buckets: buckets}
+	if n > 1 {
+		merged.Counts = make([]uint64, n-1)
+	}
+	for _, h := range hs {
+		if h == nil {
+			continue
+		}
+		j := 0
+		for i, c := range h.Counts {
+			for buckets[j] < h.Buckets[i] {
+				j++
+			}
+			merged.Counts[j] += c
+		}
+	}
+	return merged
+}
@@ -0,0 +1,348 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package metrics_test
+
+import (
+	"math"
+	"reflect"
+	"runtime/metrics"
+	"testing"
+)
+
+func TestFloat64HistogramAdd(t *testing.T) {
+	buckets := []float64{math.Inf(-1), 0, 1, 2, math.Inf(1)}
+	h := &metrics.Float64Histogram{
+		Counts:  []uint64{1, 2, 3, 4},
+		Buckets: buckets,
+	}
+	other := &metrics.Float64Histogram{
+		Counts:  []uint64{10, 0, 30, 1},
+		Buckets: []float64{math.Inf(-1), 0, 1, 2, math.Inf(1)},
+	}
+	if err := h.Add(other); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if want := []uint64{11, 2, 33, 5}; !reflect.DeepEqual(h.Counts, want) {
+		t.Errorf("Add produced counts %v, want %v", h.Counts, want)
+	}
+	if want := []uint64{10, 0, 30, 1}; !reflect.DeepEqual(other.Counts, want) {
+		t.Errorf("Add modified its argument: got counts %v, want %v", other.Counts, want)
+	}
+
+	// Accumulating into a zeroed histogram should just copy the counts.
+	for _, zero := range []*metrics.Float64Histogram{
+		{Counts: make([]uint64, len(buckets)-1), Buckets: buckets},
+		{Buckets: buckets},
+	} {
+		if err := zero.Add(other); err != nil {
+			t.Fatalf("Add into zeroed histogram failed: %v", err)
+		}
+		if !reflect.DeepEqual(zero.Counts, other.Counts) {
+			t.Errorf("Add into zeroed histogram produced counts %v, want %v", zero.Counts, other.Counts)
+		}
+	}
+}
+
+func TestFloat64HistogramAddMismatch(t *testing.T) {
+	h := &metrics.Float64Histogram{
+		Counts:  []uint64{1, 2},
+		Buckets: []float64{0, 1, 2},
+	}
+	for _, other := range []*metrics.Float64Histogram{
+		{Counts: []uint64{1, 2}, Buckets: []float64{0, 1, 3}},
+		{Counts: []uint64{1, 2, 3}, Buckets: []float64{0, 1, 2, 3}},
+		{Counts: []uint64{1}, Buckets: []float64{0, 1, 2}},
+	} {
+		if err := h.Add(other); err == nil {
+			t.Errorf("Add of histogram with buckets %v into %v succeeded", other.Buckets, h.Buckets)
+		}
+		if want := []uint64{1, 2}; !reflect.DeepEqual(h.Counts, want) {
+			t.Errorf("failed Add modified counts: got %v, want %v", h.Counts, want)
+		}
+	}
+}
+
+func TestFloat64HistogramSub(t *testing.T) {
+	buckets := []float64{math.Inf(-1), 0, 1, 2, math.Inf(1)}
+	cur := &metrics.Float64Histogram{
+		Counts:  []uint64{5, 7, 30, 4},
+		Buckets: buckets,
+	}
+	prev := &metrics.Float64Histogram{
+		Counts:  []uint64{1, 7, 10, 0},
+		Buckets: buckets,
+	}
+	delta, err := cur.Sub(prev)
+	if err != nil {
+		t.Fatalf("Sub failed: %v", err)
+	}
+	if want := []uint64{4, 0, 20, 4}; !reflect.DeepEqual(delta.Counts, want) {
+		t.Errorf("Sub produced counts %v, want %v", delta.Counts, want)
+	}
+	if !reflect.DeepEqual(delta.Buckets, buckets) {
+		t.Errorf("Sub produced buckets %v, want %v", delta.Buckets, buckets)
+	}
+	if want := []uint64{5, 7, 30, 4}; !reflect.DeepEqual(cur.Counts, want) {
+		t.Errorf("Sub modified its receiver: got counts %v, want %v", cur.Counts, want)
+	}
+
+	// A count going backwards indicates a reset.
+	if _, err := prev.Sub(cur); err == nil {
+		t.Error("Sub succeeded for decreasing counts")
+	}
+
+	// Mismatched layouts can't be subtracted.
+	other := &metrics.Float64Histogram{
+		Counts:  []uint64{1, 2},
+		Buckets: []float64{0, 1, 2},
+	}
+	if _, err := cur.Sub(other); err == nil {
+		t.Error("Sub succeeded for mismatched buckets")
+	}
+}
+
+func TestFloat64HistogramCumulativeCounts(t *testing.T) {
+	h := &metrics.Float64Histogram{
+		Counts:  []uint64{3, 0, 5, 1},
+		Buckets: []float64{0, 1, 2, 3, 4},
+	}
+	if got, want := h.CumulativeCounts(), []uint64{3, 3, 8, 9}; !reflect.DeepEqual(got, want) {
+		t.Errorf("CumulativeCounts = %v, want %v", got, want)
+	}
+
+	// Check a real histogram: counts never decrease and the last
+	// holds the total.
+	s := []metrics.Sample{{Name: "/gc/heap/allocs-by-size:bytes"}}
+	metrics.Read(s)
+	h = s[0].Value.Float64Histogram()
+	cum := h.CumulativeCounts()
+	if len(cum) != len(h.Counts) {
+		t.Fatalf("CumulativeCounts returned %d counts, want %d", len(cum), len(h.Counts))
+	}
+	var total uint64
+	for i, c := range h.Counts {
+		total += c
+		if i > 0 && cum[i] < cum[i-1] {
+			t.Errorf("CumulativeCounts decreased at %d: %d < %d", i, cum[i], cum[i-1])
+		}
+	}
+	if last := cum[len(cum)-1]; last != total {
+		t.Errorf("last cumulative count is %d, want total count %d", last, total)
+	}
+
+	// Sums saturate instead of overflowing.
+	h = &metrics.Float64Histogram{
+		Counts:  []uint64{math.MaxUint64 - 1, 2, 1},
+		Buckets: []float64{0, 1, 2, 3},
+	}
+	if got, want := h.CumulativeCounts(), []uint64{math.MaxUint64 - 1, math.MaxUint64, math.MaxUint64}; !reflect.DeepEqual(got, want) {
+		t.Errorf("CumulativeCounts = %v, want %v", got, want)
+	}
+}
+
+func TestFloat64HistogramTrim(t *testing.T) {
+	buckets := []float64{math.Inf(-1), 0, 1, 2, 3, math.Inf(1)}
+	for _, test := range []struct {
+		name        string
+		counts      []uint64
+		wantCounts  []uint64
+		wantBuckets []float64
+	}{
+		{"LeadingZeros", []uint64{0, 0, 4, 1, 2}, []uint64{4, 1, 2}, []float64{1, 2, 3, math.Inf(1)}},
+		{"TrailingZeros", []uint64{1, 0, 3, 0, 0}, []uint64{1, 0, 3}, []float64{math.Inf(-1), 0, 1, 2}},
+		{"BothEnds", []uint64{0, 5, 0, 6, 0}, []uint64{5, 0, 6}, []float64{0, 1, 2, 3}},
+		{"NoZeros", []uint64{1, 2, 3, 4, 5}, []uint64{1, 2, 3, 4, 5}, buckets},
+		{"AllZeros", []uint64{0, 0, 0, 0, 0}, nil, nil},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			h := &metrics.Float64Histogram{Counts: test.counts, Buckets: buckets}
+			trimmed := h.Trim()
+			if !reflect.DeepEqual(trimmed.Counts, test.wantCounts) {
+				t.Errorf("Trim counts = %v, want %v", trimmed.Counts, test.wantCounts)
+			}
+			if !reflect.DeepEqual(trimmed.Buckets, test.wantBuckets) {
+				t.Errorf("Trim buckets = %v, want %v", trimmed.Buckets, test.wantBuckets)
+			}
+			var total, trimmedTotal uint64
+			for _, c := range h.Counts {
+				total += c
+			}
+			for _, c := range trimmed.Counts {
+				trimmedTotal += c
+			}
+			if trimmedTotal != total {
+				t.Errorf("Trim changed the total count from %d to %d", total, trimmedTotal)
+			}
+		})
+	}
+}
+
+func TestFloat64HistogramBucketize(t *testing.T) {
+	h := &metrics.Float64Histogram{
+		Counts:  []uint64{1, 0, 5, 2},
+		Buckets: []float64{math.Inf(-1), 0, 1, 2, math.Inf(1)},
+	}
+	want := []metrics.HistogramBucket{
+		{Lower: math.Inf(-1), Upper: 0, Count: 1},
+		{Lower: 0, Upper: 1, Count: 0},
+		{Lower: 1, Upper: 2, Count: 5},
+		{Lower: 2, Upper: math.Inf(1), Count: 2},
+	}
+	if got := h.Bucketize(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Bucketize = %v, want %v", got, want)
+	}
+
+	// Check a real histogram.
+	s := []metrics.Sample{{Name: "/gc/heap/allocs-by-size:bytes"}}
+	metrics.Read(s)
+	h = s[0].Value.Float64Histogram()
+	buckets := h.Bucketize()
+	if len(buckets) != len(h.Counts) {
+		t.Fatalf("Bucketize returned %d buckets, want %d", len(buckets), len(h.Counts))
+	}
+	for i, b := range buckets {
+		if b.Lower != h.Buckets[i] || b.Upper != h.Buckets[i+1] || b.Count != h.Counts[i] {
+			t.Errorf("bucket %d is %+v, want [%v, %v) with count %d", i, b, h.Buckets[i], h.Buckets[i+1], h.Counts[i])
+		}
+	}
+}
+
+func TestFloat64HistogramScaleCounts(t *testing.T) {
+	buckets := []float64{0, 1, 2, 3, 4}
+	for _, test := range []struct {
+		name   string
+		counts []uint64
+		factor float64
+		want   []uint64
+	}{
+		{"Double", []uint64{0, 1, 5, 100}, 2, []uint64{0, 2, 10, 200}},
+		{"Fraction", []uint64{1, 2, 3, 10}, 0.25, []uint64{0, 1, 1, 3}},
+		{"HalfAwayFromZero", []uint64{1, 3, 5, 7}, 0.5, []uint64{1, 2, 3, 4}},
+		{"Overflow", []uint64{1, math.MaxUint64 / 2, math.MaxUint64, 0}, 4, []uint64{4, math.MaxUint64, math.MaxUint64, 0}},
+		{"Negative", []uint64{1, 2, 3, 4}, -1, []uint64{0, 0, 0, 0}},
+		{"NaN", []uint64{1, 2, 3, 4}, math.NaN(), []uint64{0, 0, 0, 0}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			h := &metrics.Float64Histogram{Counts: test.counts, Buckets: buckets}
+			scaled := h.ScaleCounts(test.factor)
+			if !reflect.DeepEqual(scaled.Counts, test.want) {
+				t.Errorf("ScaleCounts(%v) counts = %v, want %v", test.factor, scaled.Counts, test.want)
+			}
+			if !reflect.DeepEqual(scaled.Buckets, buckets) {
+				t.Errorf("ScaleCounts(%v) buckets = %v, want %v", test.factor, scaled.Buckets, buckets)
+			}
+		})
+	}
+}
+
+func TestFloat64HistogramDownsample(t *testing.T) {
+	inf := math.Inf(1)
+	h := &metrics.Float64Histogram{
+		Counts:  []uint64{1, 2, 3, 4, 5, 6},
+		Buckets: []float64{-inf, 0, 1, 2, 3, 4, inf},
+	}
+	for _, test := range []struct {
+		name       string
+		boundaries []float64
+		want       []uint64
+	}{
+		{"Aligned", []float64{-inf, 1, 3, inf}, []uint64{3, 7, 11}},
+		{"Single", []float64{-inf, inf}, []uint64{21}},
+		{"Identity", h.Buckets, h.Counts},
+		// Buckets [0, 1) and [3, 4) straddle new boundaries and are
+		// assigned by their lower bounds.
+		{"Unaligned", []float64{-inf, 0.5, 3.5, inf}, []uint64{3, 12, 6}},
+		{"Wider", []float64{-inf, -10, 2, inf}, []uint64{1, 5, 15}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			down, err := h.Downsample(test.boundaries)
+			if err != nil {
+				t.Fatalf("Downsample(%v): %v", test.boundaries, err)
+			}
+			if !reflect.DeepEqual(down.Counts, test.want) {
+				t.Errorf("Downsample(%v) counts = %v, want %v", test.boundaries, down.Counts, test.want)
+			}
+			if !reflect.DeepEqual(down.Buckets, test.boundaries) {
+				t.Errorf("Downsample(%v) buckets = %v", test.boundaries, down.Buckets)
+			}
+			var total uint64
+			for _, c := range down.Counts {
+				total += c
+			}
+			if total != 21 {
+				t.Errorf("Downsample(%v) total count = %d, want 21", test.boundaries, total)
+			}
+		})
+	}
+}
+
+func TestFloat64HistogramDownsampleMismatch(t *testing.T) {
+	inf := math.Inf(1)
+	h := &metrics.Float64Histogram{
+		Counts:  []uint64{1, 2, 3},
+		Buckets: []float64{-inf, 0, 1, inf},
+	}
+	for _, boundaries := range [][]float64{
+		nil,
+		{0},
+		{-inf, 1, 1, inf},
+		{-inf, 2, 1, inf},
+		{-inf, math.NaN(), inf},
+		{0, 1, inf},
+		{-inf, 0, 1},
+	} {
+		if down, err := h.Downsample(boundaries); err == nil {
+			t.Errorf("Downsample(%v) = %v, want error", boundaries, down)
+		}
+	}
+}
+
+func TestMergeHistograms(t *testing.T) {
+	a := &metrics.Float64Histogram{
+		Counts:  []uint64{1, 2, 3},
+		Buckets: []float64{0, 1, 2, 4},
+	}
+	// Overlaps a, splitting its bucket [2, 4), and extends past it.
+	b := &metrics.Float64Histogram{
+		Counts:  []uint64{4, 5, 6},
+		Buckets: []float64{1, 3, 5, 6},
+	}
+	// Disjoint from both a and b.
+	c := &metrics.Float64Histogram{
+		Counts:  []uint64{7},
+		Buckets: []float64{10, 20},
+	}
+	merged := metrics.MergeHistograms(a, nil, b, c)
+
+	wantBuckets := []float64{0, 1, 2, 3, 4, 5, 6, 10, 20}
+	if !reflect.DeepEqual(merged.Buckets, wantBuckets) {
+		t.Errorf("merged buckets = %v, want %v", merged.Buckets, wantBuckets)
+	}
+	// b's [3, 5) is assigned to [3, 4) by its lower bound.
+	wantCounts := []uint64{1, 6, 3, 5, 0, 6, 0, 7}
+	if !reflect.DeepEqual(merged.Counts, wantCounts) {
+		t.Errorf("merged counts = %v, want %v", merged.Counts, wantCounts)
+	}
+	var total uint64
+	for _, c := range merged.Counts {
+		total += c
+	}
+	if total != 28 {
+		t.Errorf("merged total count = %d, want 28", total)
+	}
+	if !reflect.DeepEqual(a.Counts, []uint64{1, 2, 3}) || !reflect.DeepEqual(a.Buckets, []float64{0, 1, 2, 4}) {
+		t.Errorf("MergeHistograms modified its input: %v", a)
+	}
+
+	// Histograms with the same buckets merge as with Add.
+	same := metrics.MergeHistograms(a, a)
+	if want := []uint64{2, 4, 6}; !reflect.DeepEqual(same.Counts, want) || !reflect.DeepEqual(same.Buckets, a.Buckets) {
+		t.Errorf("MergeHistograms(a, a) = %v, want counts %v and buckets %v", same, want, a.Buckets)
+	}
+
+	if got := metrics.MergeHistograms(); got != nil {
+		t.Errorf("MergeHistograms() = %v, want nil", got)
+	}
+}
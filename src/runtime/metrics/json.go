@@ -0,0 +1,122 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package metrics
+
+import (
+	"io"
+	"math"
+	"strconv"
+	"unicode/utf8"
+)
+
+// WriteJSON reads all supported metrics and writes them to w as a single
+// JSON object, mapping each metric's name to its value, followed by a
+// newline. The object itself contains no newlines, so appending the output
+// of successive calls to a file produces newline-delimited JSON, with one
+// snapshot per line.
+//
+// Scalar metrics are written as JSON numbers and string metrics as JSON
+// strings. Histogram metrics are written as objects with a "buckets" field,
+// holding the bucket boundaries, and a "counts" field, holding the count in
+// each bucket, as in Float64Histogram. Since JSON has no representation for
+// them, infinite and NaN floating-point values, which include the outermost
+// boundaries of many histograms, are written as the strings "+Inf", "-Inf",
+// and "NaN".
+//
+// Metrics that are unsupported on the current platform are omitted.
+func WriteJSON(w io.Writer) error {
+	samples := allSamples()
+	Read(samples)
+	_, err := w.Write(appendJSON(nil, samples))
+	return err
+}
+
+// appendJSON appends samples to buf in the format written by WriteJSON.
+func appendJSON(buf []byte, samples []Sample) []byte {
+	buf = append(buf, '{')
+	first := true
+	for _, s := range samples {
+		switch s.Value.kind {
+		case KindUint64, KindFloat64, KindString, KindFloat64Histogram:
+		default:
+			continue
+		}
+		if !first {
+			buf = append(buf, ',')
+		}
+		first = false
+		buf = appendJSONString(buf, s.Name)
+		buf = append(buf, ':')
+
+		switch s.Value.kind {
+		case KindUint64:
+			buf = strconv.AppendUint(buf, s.Value.scalar, 10)
+		case KindFloat64:
+			buf = appendJSONFloat(buf, math.Float64frombits(s.Value.scalar))
+		case KindString:
+			buf = appendJSONString(buf, s.Value.StringValue())
+		case KindFloat64Histogram:
+			h := s.Value.Float64Histogram()
+			buf = append(buf, `{"buckets":[`...)
+			for i, b := range h.Buckets {
+				if i > 0 {
+					buf = append(buf, ',')
+				}
+				buf = appendJSONFloat(buf, b)
+			}
+			buf = append(buf, `],"counts":[`...)
+			for i, c := range h.Counts {
+				if i > 0 {
+					buf = append(buf, ',')
+				}
+				buf = strconv.AppendUint(buf, c, 10)
+			}
+			buf = append(buf, "]}"...)
+		}
+	}
+	return append(buf, "}\n"...)
+}
+
+// appendJSONFloat appends v to buf as a JSON number, or as a
+// string if v is infinite or NaN.
+func appendJSONFloat(buf []byte, v float64) []byte {
+	switch {
+	case math.IsInf(v, 1):
+		return append(buf, `"+Inf"`...)
+	case math.IsInf(v, -1):
+		return append(buf, `"-Inf"`...)
+	case math.IsNaN(v):
+		return append(buf, `"NaN"`...)
+	}
+	return strconv.AppendFloat(buf, v, 'g', -1, 64)
+}
+
+// appendJSONString appends s to buf as a quoted JSON string.
+// Invalid UTF-8 is replaced with the Unicode replacement character.
+func appendJSONString(buf []byte, s string) []byte {
+	const hex = "0123456789abcdef"
+	buf = append(buf, '"')
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c < utf8.RuneSelf {
+			switch {
+			case c == '"' || c == '\\':
+				buf = append(buf, '\\', c)
+			case c == '\n':
+				buf = append(buf, `\n`...)
+			case c < 0x20:
+				buf = append(buf, '\\', 'u', '0', '0', hex[c>>4], hex[c&0xf])
+			default:
+				buf = append(buf, c)
+			}
+			i++
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		buf = utf8.AppendRune(buf, r)
+		i += size
+	}
+	return append(buf, '"')
+}
@@ -0,0 +1,92 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package metrics_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"reflect"
+	"runtime"
+	"runtime/metrics"
+	"testing"
+)
+
+func TestWriteJSON(t *testing.T) {
+	runtime.GC()
+	var buf bytes.Buffer
+	if err := metrics.WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.Bytes()
+	if i := bytes.IndexByte(out, '\n'); i != len(out)-1 {
+		t.Fatalf("WriteJSON output is not a single line:\n%s", out)
+	}
+
+	var m map[string]any
+	if err := json.Unmarshal(out, &m); err != nil {
+		t.Fatalf("decoding WriteJSON output: %v", err)
+	}
+	for _, d := range metrics.Supported() {
+		if _, ok := m[d.Name]; !ok {
+			t.Errorf("WriteJSON did not write %s", d.Name)
+		}
+	}
+	const scalar = "/gc/cycles/total:gc-cycles"
+	if n, ok := m[scalar].(float64); !ok || n < 1 {
+		t.Errorf("%s = %v, want a number at least 1", scalar, m[scalar])
+	}
+	const hist = "/gc/pauses:seconds"
+	var h struct {
+		Buckets []any
+		Counts  []uint64
+	}
+	raw, _ := json.Marshal(m[hist])
+	if err := json.Unmarshal(raw, &h); err != nil {
+		t.Fatalf("decoding %s: %v", hist, err)
+	}
+	if len(h.Buckets) == 0 || len(h.Counts) != len(h.Buckets)-1 {
+		t.Fatalf("%s has %d buckets and %d counts", hist, len(h.Buckets), len(h.Counts))
+	}
+	if h.Buckets[0] != "-Inf" {
+		t.Errorf("%s first bucket boundary = %v, want \"-Inf\"", hist, h.Buckets[0])
+	}
+	var total uint64
+	for _, c := range h.Counts {
+		total += c
+	}
+	if total == 0 {
+		t.Errorf("%s has no samples after a GC", hist)
+	}
+}
+
+func TestAppendJSON(t *testing.T) {
+	inf := math.Inf(1)
+	samples := []metrics.Sample{
+		{Name: "/a:events", Value: metrics.NewUint64Value(42)},
+		{Name: "/b:seconds", Value: metrics.NewFloat64Value(0.5)},
+		{Name: "/c:ratio", Value: metrics.NewFloat64Value(math.NaN())},
+		{Name: "/d:bytes"}, // KindBad, omitted.
+		{Name: "/e:seconds", Value: metrics.NewFloat64HistogramValue(&metrics.Float64Histogram{
+			Counts:  []uint64{1, 2},
+			Buckets: []float64{-inf, 1e-9, inf},
+		})},
+		{Name: "/f\"\\\n\x01\xff:events", Value: metrics.NewUint64Value(0)},
+	}
+	got := string(metrics.AppendJSON(nil, samples))
+	want := `{"/a:events":42,"/b:seconds":0.5,"/c:ratio":"NaN",` +
+		`"/e:seconds":{"buckets":["-Inf",1e-09,"+Inf"],"counts":[1,2]},` +
+		`"/f\"\\\n\u0001` + "\ufffd" + `:events":0}` + "\n"
+	if got != want {
+		t.Errorf("AppendJSON:\ngot  %s\nwant %s", got, want)
+	}
+	var m map[string]any
+	if err := json.Unmarshal([]byte(got), &m); err != nil {
+		t.Fatalf("decoding AppendJSON output: %v", err)
+	}
+	if v, ok := m["/f\"\\\n\x01\ufffd:events"]; !ok || !reflect.DeepEqual(v, 0.0) {
+		t.Errorf("escaped name decoded incorrectly: %v", m)
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package metrics
+
+import (
+	"errors"
+	"math"
+	"unsafe"
+)
+
+var (
+	errSamplesMismatch = errors.New("runtime/metrics: snapshots do not contain the same metrics in the same order")
+	errKindMismatch    = errors.New("runtime/metrics: snapshots disagree on the kind of a metric")
+)
+
+// isCumulative reports whether name is a supported metric whose
+// description says it is cumulative.
+func isCumulative(name string) bool {
+	d := lookupDesc(name)
+	return d != nil && d.Cumulative
+}
+
+// Merge folds several snapshots of the same metrics, for example taken
+// from different runtimes or test iterations, into a single new snapshot.
+//
+// Every snapshot must contain samples for the same metric names in the same
+// order. For cumulative metrics, as reported by All, the result holds the sum
+// of the snapshots' values; for histograms, that's the element-wise sum of
+// their counts. For every other metric, the result holds the value from the
+// last snapshot.
+//
+// Merge returns an error if the snapshots don't have the same names in the
+// same order, if they disagree on the Kind of a metric, or if the histograms
+// for a metric, cumulative or not, don't have identical Buckets.
+//
+// Histograms for cumulative metrics are newly allocated, while the Values
+// of all other metrics are copied from the last snapshot and so share any
+// underlying storage with it. Merge of no snapshots returns nil.
+func Merge(snapshots ...[]Sample) ([]Sample, error) {
+	if len(snapshots) == 0 {
+		return nil, nil
+	}
+	first := snapshots[0]
+	for _, s := range snapshots[1:] {
+		if len(s) != len(first) {
+			return nil, errSamplesMismatch
+		}
+		for i := range s {
+			if s[i].Name != first[i].Name {
+				return nil, errSamplesMismatch
+			}
+			if s[i].Value.kind != first[i].Value.kind {
+				return nil, errKindMismatch
+			}
+			if s[i].Value.kind == KindFloat64Histogram && !sameLayout(s[i].Value.Float64Histogram(), first[i].Value.Float64Histogram()) {
+				return nil, errBucketsMismatch
+			}
+		}
+	}
+
+	last := snapshots[len(snapshots)-1]
+	merged := make([]Sample, len(first))
+	for i := range merged {
+		merged[i].Name = first[i].Name
+		if !isCumulative(first[i].Name) {
+			merged[i].Value = last[i].Value
+			continue
+		}
+		v := Value{kind: first[i].Value.kind}
+		switch v.kind {
+		case KindUint64:
+			for _, s := range snapshots {
+				v.scalar += s[i].Value.scalar
+			}
+		case KindFloat64:
+			var sum float64
+			for _, s := range snapshots {
+				sum += math.Float64frombits(s[i].Value.scalar)
+			}
+			v.scalar = math.Float64bits(sum)
+		case KindFloat64Histogram:
+			h := &Float64Histogram{Buckets: first[i].Value.Float64Histogram().Buckets}
+			for _, s := range snapshots {
+				if err := h.Add(s[i].Value.Float64Histogram()); err != nil {
+					return nil, err
+				}
+			}
+			v.pointer = unsafe.Pointer(h)
+		default:
+			v = last[i].Value
+		}
+		merged[i].Value = v
+	}
+	return merged, nil
+}
+
+// sameLayout reports whether a and b have identical Buckets and the
+// same number of Counts, or are both nil.
+func sameLayout(a, b *Float64Histogram) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return sameBuckets(a.Buckets, b.Buckets) && len(a.Counts) == len(b.Counts)
+}
+
+// Changed returns the samples in cur whose values differ from those of the
+// corresponding samples in prev, in the order they appear in cur.
+//
+// prev and cur must contain samples for the same metric names in the same
+// order, as produced by two Reads of the same []Sample, otherwise Changed
+// returns an error. Values of different kinds always differ. Float64 values
+// are equal if they're numerically equal or both NaN, and histograms are
+// equal if they have identical Buckets and Counts.
+//
+// The returned samples share any underlying storage with cur.
+func Changed(prev, cur []Sample) ([]Sample, error) {
+	if len(prev) != len(cur) {
+		return nil, errSamplesMismatch
+	}
+	var changed []Sample
+	for i := range cur {
+		if cur[i].Name != prev[i].Name {
+			return nil, errSamplesMismatch
+		}
+		if !sameValue(prev[i].Value, cur[i].Value) {
+			changed = append(changed, cur[i])
+		}
+	}
+	return changed, nil
+}
+
+// sameValue reports whether a and b hold the same value, as described
+// by Changed.
+func sameValue(a, b Value) bool {
+	if a.kind != b.kind {
+		return false
+	}
+	switch a.kind {
+	case KindUint64:
+		return a.scalar == b.scalar
+	case KindFloat64:
+		x, y := math.Float64frombits(a.scalar), math.Float64frombits(b.scalar)
+		return x == y || (math.IsNaN(x) && math.IsNaN(y))
+	case KindFloat64Histogram:
+		x, y := (*Float64Histogram)(a.pointer), (*Float64Histogram)(b.pointer)
+		if !sameLayout(x, y) {
+			return false
+		}
+		if x == nil {
+			return true
+		}
+		for i := range x.Counts {
+			if x.Counts[i] != y.Counts[i] {
+				return false
+			}
+		}
+		return true
+	case KindString:
+		return a.StringValue() == b.StringValue()
+	}
+	return true
+}
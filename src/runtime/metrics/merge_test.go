@@ -0,0 +1,175 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package metrics_test
+
+import (
+	"reflect"
+	"runtime/metrics"
+	"testing"
+)
+
+const (
+	mergeCounter   = "/gc/cycles/total:gc-cycles"         // cumulative uint64
+	mergeCPU       = "/cpu/classes/gc/pause:cpu-seconds"  // cumulative float64
+	mergeHistogram = "/gc/pauses:seconds"                 // cumulative histogram
+	mergeGauge     = "/gc/heap/goal:bytes"                // non-cumulative uint64
+	mergeStacks    = "/sched/goroutines/stack-size:bytes" // non-cumulative histogram
+)
+
+func mergeSnapshot(counter uint64, cpu float64, counts []uint64, gauge uint64) []metrics.Sample {
+	return []metrics.Sample{
+		{Name: mergeCounter, Value: metrics.NewUint64Value(counter)},
+		{Name: mergeCPU, Value: metrics.NewFloat64Value(cpu)},
+		{Name: mergeHistogram, Value: metrics.NewFloat64HistogramValue(&metrics.Float64Histogram{
+			Counts:  counts,
+			Buckets: []float64{0, 1, 2, 3},
+		})},
+		{Name: mergeGauge, Value: metrics.NewUint64Value(gauge)},
+		{Name: mergeStacks, Value: metrics.NewFloat64HistogramValue(&metrics.Float64Histogram{
+			Counts:  []uint64{gauge, gauge},
+			Buckets: []float64{0, 4096, 8192},
+		})},
+	}
+}
+
+func TestMerge(t *testing.T) {
+	for _, test := range []struct {
+		name        string
+		snapshots   [][]metrics.Sample
+		wantCounter uint64
+		wantCPU     float64
+		wantCounts  []uint64
+		wantGauge   uint64
+	}{
+		{
+			name: "Two",
+			snapshots: [][]metrics.Sample{
+				mergeSnapshot(1, 0.5, []uint64{1, 0, 2}, 100),
+				mergeSnapshot(2, 1.5, []uint64{0, 3, 1}, 200),
+			},
+			wantCounter: 3,
+			wantCPU:     2,
+			wantCounts:  []uint64{1, 3, 3},
+			wantGauge:   200,
+		},
+		{
+			name: "Three",
+			snapshots: [][]metrics.Sample{
+				mergeSnapshot(1, 0.5, []uint64{1, 0, 2}, 300),
+				mergeSnapshot(2, 1.5, []uint64{0, 3, 1}, 100),
+				mergeSnapshot(4, 0.25, []uint64{5, 5, 5}, 200),
+			},
+			wantCounter: 7,
+			wantCPU:     2.25,
+			wantCounts:  []uint64{6, 8, 8},
+			wantGauge:   200,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			merged, err := metrics.Merge(test.snapshots...)
+			if err != nil {
+				t.Fatalf("Merge: %v", err)
+			}
+			if got := merged[0].Value.Uint64(); got != test.wantCounter {
+				t.Errorf("merged %s = %d, want %d", mergeCounter, got, test.wantCounter)
+			}
+			if got := merged[1].Value.Float64(); got != test.wantCPU {
+				t.Errorf("merged %s = %f, want %f", mergeCPU, got, test.wantCPU)
+			}
+			if got := merged[2].Value.Float64Histogram().Counts; !reflect.DeepEqual(got, test.wantCounts) {
+				t.Errorf("merged %s counts = %v, want %v", mergeHistogram, got, test.wantCounts)
+			}
+			if got := merged[3].Value.Uint64(); got != test.wantGauge {
+				t.Errorf("merged %s = %d, want %d", mergeGauge, got, test.wantGauge)
+			}
+			if got, want := merged[4].Value.Float64Histogram().Counts, []uint64{test.wantGauge, test.wantGauge}; !reflect.DeepEqual(got, want) {
+				t.Errorf("merged %s counts = %v, want %v", mergeStacks, got, want)
+			}
+			for i := range merged {
+				if merged[i].Name != test.snapshots[0][i].Name {
+					t.Errorf("merged sample %d is %s, want %s", i, merged[i].Name, test.snapshots[0][i].Name)
+				}
+			}
+			// The inputs must be left alone.
+			if got := test.snapshots[0][2].Value.Float64Histogram().Counts; reflect.DeepEqual(got, test.wantCounts) {
+				t.Errorf("Merge modified the first snapshot's histogram")
+			}
+		})
+	}
+}
+
+func TestMergeMismatch(t *testing.T) {
+	good := mergeSnapshot(1, 1, []uint64{1, 1, 1}, 1)
+
+	reordered := mergeSnapshot(1, 1, []uint64{1, 1, 1}, 1)
+	reordered[0], reordered[1] = reordered[1], reordered[0]
+
+	short := mergeSnapshot(1, 1, []uint64{1, 1, 1}, 1)[:3]
+
+	wrongKind := mergeSnapshot(1, 1, []uint64{1, 1, 1}, 1)
+	wrongKind[0].Value = metrics.NewFloat64Value(1)
+
+	wrongBuckets := mergeSnapshot(1, 1, []uint64{1, 1, 1}, 1)
+	wrongBuckets[2].Value = metrics.NewFloat64HistogramValue(&metrics.Float64Histogram{
+		Counts:  []uint64{1, 1, 1},
+		Buckets: []float64{0, 1, 2, 4},
+	})
+
+	wrongGaugeBuckets := mergeSnapshot(1, 1, []uint64{1, 1, 1}, 1)
+	wrongGaugeBuckets[4].Value = metrics.NewFloat64HistogramValue(&metrics.Float64Histogram{
+		Counts:  []uint64{1, 1, 1},
+		Buckets: []float64{0, 4096, 8192, 16384},
+	})
+
+	for _, test := range []struct {
+		name string
+		bad  []metrics.Sample
+	}{
+		{"Order", reordered},
+		{"Length", short},
+		{"Kind", wrongKind},
+		{"Buckets", wrongBuckets},
+		{"NonCumulativeBuckets", wrongGaugeBuckets},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := metrics.Merge(good, test.bad); err == nil {
+				t.Error("Merge of mismatched snapshots succeeded")
+			}
+		})
+	}
+}
+
+func TestChanged(t *testing.T) {
+	prev := mergeSnapshot(1, 0.5, []uint64{1, 0, 2}, 100)
+	cur := mergeSnapshot(2, 0.5, []uint64{1, 1, 2}, 100)
+
+	changed, err := metrics.Changed(prev, cur)
+	if err != nil {
+		t.Fatalf("Changed: %v", err)
+	}
+	var names []string
+	for _, s := range changed {
+		names = append(names, s.Name)
+	}
+	if want := []string{mergeCounter, mergeHistogram}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("Changed returned %v, want %v", names, want)
+	}
+	if got := changed[0].Value.Uint64(); got != 2 {
+		t.Errorf("changed %s = %d, want the current value 2", mergeCounter, got)
+	}
+
+	if changed, err := metrics.Changed(cur, cur); err != nil || len(changed) != 0 {
+		t.Errorf("Changed of identical snapshots = %v, %v; want no samples", changed, err)
+	}
+
+	reordered := mergeSnapshot(2, 0.5, []uint64{1, 1, 2}, 100)
+	reordered[0], reordered[1] = reordered[1], reordered[0]
+	if _, err := metrics.Changed(prev, reordered); err == nil {
+		t.Error("Changed of reordered snapshots succeeded")
+	}
+	if _, err := metrics.Changed(prev, cur[:3]); err == nil {
+		t.Error("Changed of snapshots of different lengths succeeded")
+	}
+}
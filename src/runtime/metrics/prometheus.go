@@ -0,0 +1,189 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package metrics
+
+import (
+	"io"
+	"math"
+	"strconv"
+)
+
+// WritePrometheus reads all supported metrics and writes them to w in the
+// Prometheus text exposition format, each preceded by HELP and TYPE comments.
+//
+// Metric names are mangled to fit the Prometheus naming rules: the leading
+// '/' is dropped, every character other than an ASCII letter, digit, or
+// underscore is replaced with an underscore, and the result is prefixed with
+// "go_". For example, "/memory/classes/heap/free:bytes" becomes
+// "go_memory_classes_heap_free_bytes" and "/gc/pacer/alloc-rate:bytes/second"
+// becomes "go_gc_pacer_alloc_rate_bytes_second".
+//
+// Cumulative scalar metrics have TYPE counter and, following the Prometheus
+// convention for counters, an additional "_total" suffix. All other scalar
+// metrics have TYPE gauge. String metrics are written as a gauge with value 1
+// and the string in a "value" label.
+//
+// Cumulative histogram metrics have TYPE histogram. Prometheus histograms must
+// only ever grow, so other histogram metrics, which describe a point-in-time
+// distribution, have TYPE untyped. Both are written as _bucket series with
+// cumulative counts, labeled by the upper bound of each bucket in "le",
+// followed by _sum and _count series. A final "+Inf" bucket is added if the
+// histogram's last bucket is bounded. Since the runtime doesn't track the sum
+// of the values in a histogram, _sum is an estimate that places every value
+// at the midpoint of its bucket, or at the bucket's finite bound if the other
+// bound is infinite.
+//
+// Metrics that are unsupported on the current platform are omitted.
+func WritePrometheus(w io.Writer) error {
+	samples := allSamples()
+	Read(samples)
+	_, err := w.Write(appendPrometheus(nil, allDesc, samples))
+	return err
+}
+
+// appendPrometheus appends samples, described by the corresponding
+// element of descs, to buf in the format written by WritePrometheus.
+func appendPrometheus(buf []byte, descs []Description, samples []Sample) []byte {
+	for i, s := range samples {
+		name := promName(s.Name)
+		typ := "gauge"
+		switch s.Value.kind {
+		case KindUint64, KindFloat64:
+			if descs[i].Cumulative {
+				typ = "counter"
+				name += "_total"
+			}
+		case KindFloat64Histogram:
+			typ = "untyped"
+			if descs[i].Cumulative {
+				typ = "histogram"
+			}
+		case KindString:
+			// Written as a gauge, as described above.
+		default:
+			continue
+		}
+		buf = append(buf, "# HELP "...)
+		buf = append(buf, name...)
+		buf = append(buf, ' ')
+		buf = appendPromEscaped(buf, descs[i].Description, false)
+		buf = append(buf, "\n# TYPE "...)
+		buf = append(buf, name...)
+		buf = append(buf, ' ')
+		buf = append(buf, typ...)
+		buf = append(buf, '\n')
+
+		switch s.Value.kind {
+		case KindUint64:
+			buf = append(buf, name...)
+			buf = append(buf, ' ')
+			buf = strconv.AppendUint(buf, s.Value.scalar, 10)
+			buf = append(buf, '\n')
+		case KindFloat64:
+			buf = append(buf, name...)
+			buf = append(buf, ' ')
+			buf = appendPromFloat(buf, math.Float64frombits(s.Value.scalar))
+			buf = append(buf, '\n')
+		case KindString:
+			buf = append(buf, name...)
+			buf = append(buf, `{value="`...)
+			buf = appendPromEscaped(buf, s.Value.StringValue(), true)
+			buf = append(buf, "\"} 1\n"...)
+		case KindFloat64Histogram:
+			buf = appendPromHistogram(buf, name, s.Value.Float64Histogram())
+		}
+	}
+	return buf
+}
+
+// appendPromHistogram appends the _bucket, _sum, and _count series
+// for h, whose mangled name is name, to buf.
+func appendPromHistogram(buf []byte, name string, h *Float64Histogram) []byte {
+	var count uint64
+	var sum float64
+	for i, c := range h.Counts {
+		count += c
+		buf = append(buf, name...)
+		buf = append(buf, `_bucket{le="`...)
+		buf = appendPromFloat(buf, h.Buckets[i+1])
+		buf = append(buf, "\"} "...)
+		buf = strconv.AppendUint(buf, count, 10)
+		buf = append(buf, '\n')
+
+		if c == 0 {
+			continue
+		}
+		lo, hi := h.Buckets[i], h.Buckets[i+1]
+		switch {
+		case math.IsInf(lo, -1):
+			sum += float64(c) * hi
+		case math.IsInf(hi, 1):
+			sum += float64(c) * lo
+		default:
+			sum += float64(c) * (lo + (hi-lo)/2)
+		}
+	}
+	if len(h.Buckets) == 0 || !math.IsInf(h.Buckets[len(h.Buckets)-1], 1) {
+		buf = append(buf, name...)
+		buf = append(buf, `_bucket{le="+Inf"} `...)
+		buf = strconv.AppendUint(buf, count, 10)
+		buf = append(buf, '\n')
+	}
+	buf = append(buf, name...)
+	buf = append(buf, "_sum "...)
+	buf = appendPromFloat(buf, sum)
+	buf = append(buf, '\n')
+	buf = append(buf, name...)
+	buf = append(buf, "_count "...)
+	buf = strconv.AppendUint(buf, count, 10)
+	return append(buf, '\n')
+}
+
+// promName returns the Prometheus metric name for the metric
+// with the given name, as described by WritePrometheus.
+func promName(name string) string {
+	b := make([]byte, 0, len("go_")+len(name))
+	b = append(b, "go_"...)
+	for i := 1; i < len(name); i++ {
+		c := name[i]
+		if !('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || c == '_') {
+			c = '_'
+		}
+		b = append(b, c)
+	}
+	return string(b)
+}
+
+// appendPromFloat appends v to buf using the Prometheus
+// spelling of the special values.
+func appendPromFloat(buf []byte, v float64) []byte {
+	switch {
+	case math.IsInf(v, 1):
+		return append(buf, "+Inf"...)
+	case math.IsInf(v, -1):
+		return append(buf, "-Inf"...)
+	case math.IsNaN(v):
+		return append(buf, "NaN"...)
+	}
+	return strconv.AppendFloat(buf, v, 'g', -1, 64)
+}
+
+// appendPromEscaped appends s to buf, escaping backslashes and
+// newlines, and also double quotes if s is a label value.
+func appendPromEscaped(buf []byte, s string, label bool) []byte {
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\\':
+			buf = append(buf, `\\`...)
+		case c == '\n':
+			buf = append(buf, `\n`...)
+		case c == '"' && label:
+			buf = append(buf, `\"`...)
+		default:
+			buf = append(buf, c)
+		}
+	}
+	return buf
+}
@@ -0,0 +1,114 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package metrics_test
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"math"
+	"os"
+	"path/filepath"
+	"runtime/metrics"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func TestPrometheusGolden(t *testing.T) {
+	descs := []metrics.Description{
+		{
+			Name:        "/gc/cycles/total:gc-cycles",
+			Description: "Count of all completed GC cycles.",
+			Kind:        metrics.KindUint64,
+			Cumulative:  true,
+		},
+		{
+			Name:        "/gc/pacer/alloc-rate:bytes/second",
+			Description: "Estimated allocation rate.\nSecond line with a \\ backslash.",
+			Kind:        metrics.KindFloat64,
+		},
+		{
+			Name:        "/gc/pauses:seconds",
+			Description: "Distribution of individual GC-related stop-the-world pause latencies.",
+			Kind:        metrics.KindFloat64Histogram,
+			Cumulative:  true,
+		},
+		{
+			Name:        "/gc/heap/sizes:bytes",
+			Description: "A bounded histogram.",
+			Kind:        metrics.KindFloat64Histogram,
+		},
+		{
+			Name:        "/unsupported:bytes",
+			Description: "Not written at all.",
+			Kind:        metrics.KindUint64,
+		},
+	}
+	samples := []metrics.Sample{
+		{Name: descs[0].Name, Value: metrics.NewUint64Value(42)},
+		{Name: descs[1].Name, Value: metrics.NewFloat64Value(1.5e6)},
+		{Name: descs[2].Name, Value: metrics.NewFloat64HistogramValue(&metrics.Float64Histogram{
+			Counts:  []uint64{1, 0, 3, 2},
+			Buckets: []float64{math.Inf(-1), 0.001, 0.01, 0.1, math.Inf(1)},
+		})},
+		{Name: descs[3].Name, Value: metrics.NewFloat64HistogramValue(&metrics.Float64Histogram{
+			Counts:  []uint64{4, 1},
+			Buckets: []float64{0, 8, 16},
+		})},
+		{Name: descs[4].Name},
+	}
+	got := metrics.AppendPrometheus(nil, descs, samples)
+
+	golden := filepath.Join("testdata", "prometheus.golden")
+	if *update {
+		if err := os.WriteFile(golden, got, 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("Prometheus output does not match %s:\ngot:\n%s\nwant:\n%s", golden, got, want)
+	}
+}
+
+func TestWritePrometheus(t *testing.T) {
+	var buf bytes.Buffer
+	if err := metrics.WritePrometheus(&buf); err != nil {
+		t.Fatalf("WritePrometheus failed: %v", err)
+	}
+	found := false
+	sc := bufio.NewScanner(&buf)
+	for sc.Scan() {
+		line := sc.Text()
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, value, ok := strings.Cut(line, " ")
+		if !ok || value == "" {
+			t.Errorf("malformed sample line %q", line)
+			continue
+		}
+		if i := strings.IndexByte(name, '{'); i >= 0 {
+			name = name[:i]
+		}
+		for _, c := range name {
+			if !('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || c == '_') {
+				t.Errorf("sample line %q has invalid metric name", line)
+				break
+			}
+		}
+		if name == "go_sched_goroutines_goroutines" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("WritePrometheus did not write go_sched_goroutines_goroutines")
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package metrics
+
+import (
+	"math"
+	"time"
+)
+
+// Reading pairs the description of a metric with a sample of its value.
+type Reading struct {
+	// Description describes the metric that was read.
+	Description Description
+
+	// Value is the value of the metric at the time it was read.
+	Value Value
+}
+
+// ReadAll reads all supported metrics and returns one Reading for each
+// of them, in the same order as All.
+//
+// ReadAll is a convenience for one-off dumps of all metrics. It allocates
+// a new slice and new storage for each pointer-typed Value (for example,
+// Float64Histogram) on every call, so the results never alias those of
+// another call and may be retained freely. Programs that read metrics
+// frequently should use Read with a reused []Sample instead.
+func ReadAll() []Reading {
+	samples := allSamples()
+	Read(samples)
+
+	readings := make([]Reading, len(samples))
+	for i := range readings {
+		readings[i] = Reading{Description: allDesc[i], Value: samples[i].Value}
+	}
+	return readings
+}
+
+// Rates computes the per-second rate of change of each cumulative scalar
+// metric between two snapshots taken dt apart, for example by ReadAll.
+//
+// The result maps each metric name to (cur-prev)/dt.Seconds(). Metrics that
+// are not cumulative, that are not scalars (for example, histograms), or that
+// are missing from either snapshot are skipped, as are metrics whose Kind
+// differs between the snapshots. If dt is not positive, Rates returns nil.
+func Rates(prev, cur []Reading, dt time.Duration) map[string]float64 {
+	if dt <= 0 {
+		return nil
+	}
+	old := make(map[string]Value, len(prev))
+	for _, r := range prev {
+		old[r.Description.Name] = r.Value
+	}
+	secs := dt.Seconds()
+	rates := make(map[string]float64)
+	for _, r := range cur {
+		if !r.Description.Cumulative {
+			continue
+		}
+		p, ok := old[r.Description.Name]
+		if !ok || p.kind != r.Value.kind {
+			continue
+		}
+		var delta float64
+		switch r.Value.kind {
+		case KindUint64:
+			if c, p := r.Value.scalar, p.scalar; c >= p {
+				delta = float64(c - p)
+			} else {
+				delta = -float64(p - c)
+			}
+		case KindFloat64:
+			delta = math.Float64frombits(r.Value.scalar) - math.Float64frombits(p.scalar)
+		default:
+			continue
+		}
+		rates[r.Description.Name] = delta / secs
+	}
+	return rates
+}
+
+// RateResilient returns the per-second rate of change of a cumulative
+// counter that read prev and then, dt later, cur.
+//
+// Normally that's (cur-prev)/dt.Seconds(). But cumulative metrics only go
+// down if the counter was reset, typically because the process being
+// observed restarted between the two readings, so if cur is less than prev,
+// RateResilient assumes that the counter restarted from zero and returns
+// cur/dt.Seconds() instead. This underestimates the rate if the counter
+// counted past prev before the restart, and it cannot detect a reset
+// after which the counter has already caught up with prev.
+//
+// If dt is not positive, RateResilient returns 0.
+func RateResilient(prev, cur uint64, dt time.Duration) float64 {
+	if dt <= 0 {
+		return 0
+	}
+	if cur < prev {
+		return float64(cur) / dt.Seconds()
+	}
+	return float64(cur-prev) / dt.Seconds()
+}
@@ -0,0 +1,101 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package metrics_test
+
+import (
+	"reflect"
+	"runtime/metrics"
+	"testing"
+	"time"
+)
+
+func TestReadAll(t *testing.T) {
+	descs := metrics.All()
+	readings := metrics.ReadAll()
+	if len(readings) != len(descs) {
+		t.Fatalf("ReadAll returned %d readings, want %d", len(readings), len(descs))
+	}
+	// Every metric in All must be read, expensive ones included.
+	byName := make(map[string]metrics.Reading, len(readings))
+	for _, r := range readings {
+		byName[r.Description.Name] = r
+	}
+	for _, d := range descs {
+		if _, ok := byName[d.Name]; !ok {
+			t.Errorf("ReadAll did not read %s", d.Name)
+		}
+	}
+	for i, r := range readings {
+		if r.Description != descs[i] {
+			t.Errorf("reading %d has description %+v, want %+v", i, r.Description, descs[i])
+			continue
+		}
+		if kind := r.Value.Kind(); kind != r.Description.Kind {
+			t.Errorf("reading for %s has kind %d, want %d", r.Description.Name, kind, r.Description.Kind)
+		}
+	}
+
+	// Histograms from different calls must not share storage.
+	again := metrics.ReadAll()
+	for i, r := range readings {
+		if r.Value.Kind() != metrics.KindFloat64Histogram {
+			continue
+		}
+		h1, h2 := r.Value.Float64Histogram(), again[i].Value.Float64Histogram()
+		if h1 == h2 || (len(h1.Counts) > 0 && &h1.Counts[0] == &h2.Counts[0]) {
+			t.Errorf("histogram for %s is shared between calls to ReadAll", r.Description.Name)
+		}
+	}
+}
+
+func TestRates(t *testing.T) {
+	counter := metrics.Description{Name: "/test/counter:events", Kind: metrics.KindUint64, Cumulative: true}
+	seconds := metrics.Description{Name: "/test/time:seconds", Kind: metrics.KindFloat64, Cumulative: true}
+	gauge := metrics.Description{Name: "/test/gauge:bytes", Kind: metrics.KindUint64}
+	missing := metrics.Description{Name: "/test/missing:events", Kind: metrics.KindUint64, Cumulative: true}
+
+	prev := []metrics.Reading{
+		{Description: counter, Value: metrics.NewUint64Value(100)},
+		{Description: seconds, Value: metrics.NewFloat64Value(1.5)},
+		{Description: gauge, Value: metrics.NewUint64Value(10)},
+	}
+	cur := []metrics.Reading{
+		{Description: gauge, Value: metrics.NewUint64Value(50)},
+		{Description: seconds, Value: metrics.NewFloat64Value(2.5)},
+		{Description: counter, Value: metrics.NewUint64Value(300)},
+		{Description: missing, Value: metrics.NewUint64Value(1)},
+	}
+	rates := metrics.Rates(prev, cur, 2*time.Second)
+	want := map[string]float64{
+		counter.Name: 100,
+		seconds.Name: 0.5,
+	}
+	if !reflect.DeepEqual(rates, want) {
+		t.Errorf("Rates returned %v, want %v", rates, want)
+	}
+	if rates := metrics.Rates(prev, cur, 0); rates != nil {
+		t.Errorf("Rates with zero dt returned %v, want nil", rates)
+	}
+}
+
+func TestRateResilient(t *testing.T) {
+	for _, test := range []struct {
+		name      string
+		prev, cur uint64
+		dt        time.Duration
+		want      float64
+	}{
+		{"normal", 100, 300, 2 * time.Second, 100},
+		{"unchanged", 100, 100, time.Second, 0},
+		{"reset", 1000, 50, 500 * time.Millisecond, 100},
+		{"large", 1 << 63, 1<<63 + 1<<10, time.Second, 1 << 10},
+		{"zero dt", 100, 300, 0, 0},
+		{"negative dt", 100, 300, -time.Second, 0},
+	} {
+		if got := metrics.RateResilient(test.prev, test.cur, test.dt); got != test.want {
+			t.Errorf("%s: RateResilient(%d, %d, %v) = %v, want %v", test.name, test.prev, test.cur, test.dt, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package metrics
+
+import (
+	"errors"
+	"sync"
+)
+
+var registered struct {
+	sync.Mutex
+	descs []Description
+}
+
+// Register adds d to the catalog of metric descriptions returned by
+// AllIncludingRegistered, so that tools presenting runtime metrics can also
+// describe metrics defined by the application.
+//
+// Registration is purely descriptive: Read does not produce values for
+// registered metrics, and reports them as KindBad like any other unknown name.
+// d.Unit is ignored and replaced with the unit component of d.Name.
+//
+// Register returns an error if d.Name is not a well-formed metric name, as
+// described by the documentation for Description.Name, or if a metric with
+// that name is already supported or registered.
+func Register(d Description) error {
+	if !validName(d.Name) {
+		return errors.New("runtime/metrics: malformed metric name " + d.Name)
+	}
+	d.Unit = unitOf(d.Name)
+
+	registered.Lock()
+	defer registered.Unlock()
+	for _, descs := range [][]Description{allDesc, registered.descs} {
+		for i := range descs {
+			if descs[i].Name == d.Name {
+				return errors.New("runtime/metrics: metric " + d.Name + " is already described")
+			}
+		}
+	}
+	registered.descs = append(registered.descs, d)
+	return nil
+}
+
+// AllIncludingRegistered returns a new slice containing the descriptions
+// returned by All, followed by every description added with Register, in
+// the order they were registered.
+func AllIncludingRegistered() []Description {
+	registered.Lock()
+	defer registered.Unlock()
+	descs := make([]Description, 0, len(allDesc)+len(registered.descs))
+	descs = append(descs, allDesc...)
+	return append(descs, registered.descs...)
+}
+
+// validName reports whether name matches the format documented
+// for Description.Name, that is, the regular expression
+//
+//	^(?P<name>/[^:]+):(?P<unit>[^:*/]+(?:[*/][^:*/]+)*)$
+func validName(name string) bool {
+	colon := -1
+	for i := 0; i < len(name); i++ {
+		if name[i] == ':' {
+			if colon >= 0 {
+				return false
+			}
+			colon = i
+		}
+	}
+	if colon < 2 || name[0] != '/' {
+		return false
+	}
+	// The unit is a non-empty sequence of non-empty components
+	// separated by '*' or '/'.
+	unit := name[colon+1:]
+	start := 0
+	for i := 0; i <= len(unit); i++ {
+		if i == len(unit) || unit[i] == '*' || unit[i] == '/' {
+			if i == start {
+				return false
+			}
+			start = i + 1
+		}
+	}
+	return true
+}
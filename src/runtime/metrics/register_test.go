@@ -0,0 +1,110 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package metrics_test
+
+import (
+	"runtime/metrics"
+	"testing"
+)
+
+func TestRegister(t *testing.T) {
+	defer metrics.ResetRegistered()
+	d := metrics.Description{
+		Name:        "/test/register/requests:requests",
+		Description: "Count of requests served.",
+		Kind:        metrics.KindUint64,
+		Cumulative:  true,
+	}
+	if err := metrics.Register(d); err != nil {
+		t.Fatalf("Register(%s) failed: %v", d.Name, err)
+	}
+
+	all := metrics.AllIncludingRegistered()
+	if n := len(metrics.All()); len(all) <= n {
+		t.Fatalf("AllIncludingRegistered returned %d descriptions, want more than %d", len(all), n)
+	}
+	found := false
+	for _, got := range all {
+		if got.Name != d.Name {
+			continue
+		}
+		found = true
+		want := d
+		want.Unit = "requests"
+		if got != want {
+			t.Errorf("registered description is %+v, want %+v", got, want)
+		}
+	}
+	if !found {
+		t.Errorf("AllIncludingRegistered is missing %s", d.Name)
+	}
+	for _, got := range metrics.All() {
+		if got.Name == d.Name {
+			t.Errorf("All includes registered metric %s", d.Name)
+		}
+	}
+
+	// Registered metrics have no values.
+	s := []metrics.Sample{{Name: d.Name}}
+	metrics.Read(s)
+	if k := s[0].Value.Kind(); k != metrics.KindBad {
+		t.Errorf("Read produced a value of kind %v for a registered metric, want KindBad", k)
+	}
+}
+
+func TestRegisterDuplicate(t *testing.T) {
+	defer metrics.ResetRegistered()
+	d := metrics.Description{Name: "/test/register/duplicate:bytes"}
+	if err := metrics.Register(d); err != nil {
+		t.Fatalf("Register(%s) failed: %v", d.Name, err)
+	}
+	if err := metrics.Register(d); err == nil {
+		t.Errorf("second Register(%s) succeeded", d.Name)
+	}
+	runtimeMetric := metrics.Description{Name: metrics.All()[0].Name}
+	if err := metrics.Register(runtimeMetric); err == nil {
+		t.Errorf("Register(%s) succeeded for a runtime metric", runtimeMetric.Name)
+	}
+}
+
+func TestRegisterMalformed(t *testing.T) {
+	for _, name := range []string{
+		"",
+		"/",
+		"/test/register",
+		"test/register:bytes",
+		"/:bytes",
+		"/test/register:",
+		"/test/register:bytes:bytes",
+		"/test/register:bytes/",
+		"/test/register:*seconds",
+		"/test/register:bytes//second",
+	} {
+		if err := metrics.Register(metrics.Description{Name: name}); err == nil {
+			t.Errorf("Register(%q) succeeded for a malformed name", name)
+		}
+	}
+}
+
+func TestValidNameMatchesFormat(t *testing.T) {
+	names := []string{
+		"/test/register:bytes/second",
+		"/test/register:byte*cpu-seconds",
+		"/a:b",
+		"/:b",
+		"/a::b",
+		"a:b",
+		"/a:b*",
+		"/a:/b",
+	}
+	for _, d := range metrics.All() {
+		names = append(names, d.Name)
+	}
+	for _, name := range names {
+		if got, want := metrics.ValidName(name), nameFormat.MatchString(name); got != want {
+			t.Errorf("validName(%q) = %t, want %t", name, got, want)
+		}
+	}
+}
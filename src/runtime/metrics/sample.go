@@ -5,7 +5,10 @@
 package metrics
 
 import (
+	"context"
+	"errors"
 	_ "runtime" // depends on the runtime via a linkname'd function
+	"sync"
 	"unsafe"
 )
 
@@ -36,6 +39,12 @@ func runtime_readMetrics(unsafe.Pointer, int, int)
 // whose underlying storage will be reused by Read when possible. To safely use
 // such values in a concurrent setting, all data must be deep-copied.
 //
+// Specifically, if a Sample's Value holds a *Float64Histogram produced by an
+// earlier Read of the same metric, Read updates that Float64Histogram in place,
+// reusing its Counts slice, provided the slice still has the length the
+// metric's buckets require. Reading the same []Sample repeatedly thus settles
+// into a steady state in which Read does not allocate.
+//
 // It is safe to execute multiple Read calls concurrently, but their arguments
 // must share no underlying memory. When in doubt, create a new []Sample from
 // scratch, which is always safe, though may be inefficient.
@@ -45,3 +54,156 @@ func runtime_readMetrics(unsafe.Pointer, int, int)
 func Read(m []Sample) {
 	runtime_readMetrics(unsafe.Pointer(&m[0]), len(m), cap(m))
 }
+
+// NewSamples returns a new []Sample, ready to pass to Read, with one
+// Sample for each of the given metric names, in the same order, and with
+// zero Values. With no names, it returns a Sample for every metric
+// returned by All, in the same order as All.
+//
+// NewSamples returns an error if any of the names is not that of a
+// metric returned by All.
+func NewSamples(names ...string) ([]Sample, error) {
+	if len(names) == 0 {
+		return allSamples(), nil
+	}
+	samples := make([]Sample, len(names))
+	for i, name := range names {
+		if lookupDesc(name) == nil {
+			return nil, errors.New("runtime/metrics: unknown metric " + name)
+		}
+		samples[i].Name = name
+	}
+	return samples, nil
+}
+
+// allSamples returns a new []Sample with one Sample for each metric
+// returned by All, in the same order as All, and with zero Values.
+func allSamples() []Sample {
+	samples := make([]Sample, len(allDesc))
+	for i := range samples {
+		samples[i].Name = allDesc[i].Name
+	}
+	return samples
+}
+
+// expensive reports whether computing the metric with the given name takes
+// time proportional to something that grows with the program, such as the
+// number of goroutines, rather than a roughly constant amount of time.
+func expensive(name string) bool {
+	switch name {
+	case "/sched/goroutines/stack-size:bytes":
+		return true
+	}
+	return false
+}
+
+// ReadContext is like Read, but gives up before computing expensive metrics
+// once ctx is done.
+//
+// Most metrics are cheap to read, and ReadContext always populates those
+// first, together in a single Read, regardless of ctx. The exception is
+// metrics whose cost grows with the program, which ReadContext reads
+// afterwards, one at a time, checking ctx before each. Currently the only
+// such metric is
+//
+//	/sched/goroutines/stack-size:bytes
+//
+// which must visit every goroutine.
+//
+// If ctx is done before all the expensive metrics have been read, ReadContext
+// returns ctx.Err() and leaves the Values of the remaining expensive samples
+// unmodified. Otherwise it returns nil.
+func ReadContext(ctx context.Context, m []Sample) error {
+	// Gather the cheap samples so that they're all read at once, and
+	// thus consistently with one another, then copy them back.
+	var cheap []Sample
+	for i := range m {
+		if !expensive(m[i].Name) {
+			cheap = append(cheap, m[i])
+		}
+	}
+	switch {
+	case len(cheap) == len(m):
+		if len(m) > 0 {
+			Read(m)
+		}
+		return nil
+	case len(cheap) > 0:
+		Read(cheap)
+		j := 0
+		for i := range m {
+			if !expensive(m[i].Name) {
+				m[i] = cheap[j]
+				j++
+			}
+		}
+	}
+
+	for i := range m {
+		if !expensive(m[i].Name) {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		Read(m[i : i+1])
+	}
+	return nil
+}
+
+// forEachSamples is a pool of []Sample containing every supported metric,
+// used by ForEach to avoid allocating on each call.
+var forEachSamples = sync.Pool{
+	New: func() any {
+		samples := allSamples()
+		return &samples
+	},
+}
+
+// ForEach reads all supported metrics once and calls fn for each of them,
+// in the order returned by All, until fn returns false.
+//
+// The Value passed to fn is only valid for the duration of that call to fn.
+// In particular, the underlying storage of pointer-typed Values (for example,
+// Float64Histogram) will be reused by later calls to ForEach, so fn must not
+// retain v, or anything obtained through it, after it returns. To keep a
+// value around, fn must deep-copy it.
+//
+// It is safe to call ForEach concurrently.
+func ForEach(fn func(name string, v Value) bool) {
+	sp := forEachSamples.Get().(*[]Sample)
+	samples := *sp
+	Read(samples)
+	for i := range samples {
+		if !fn(samples[i].Name, samples[i].Value) {
+			break
+		}
+	}
+	forEachSamples.Put(sp)
+}
+
+var supported struct {
+	once  sync.Once
+	descs []Description
+}
+
+// Supported returns a new slice containing the descriptions of the metrics
+// returned by All whose values are available on the current platform, that
+// is, those which Read does not report as KindBad.
+//
+// Platform support doesn't change while a program runs, so Supported only
+// reads the metrics the first time it is called.
+func Supported() []Description {
+	supported.once.Do(func() {
+		samples := allSamples()
+		Read(samples)
+		for i := range samples {
+			if samples[i].Value.Kind() != KindBad {
+				supported.descs = append(supported.descs, allDesc[i])
+			}
+		}
+	})
+	descs := make([]Description, len(supported.descs))
+	copy(descs, supported.descs)
+	return descs
+}
@@ -0,0 +1,190 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package metrics_test
+
+import (
+	"context"
+	"runtime/metrics"
+	"testing"
+)
+
+func TestForEach(t *testing.T) {
+	descs := metrics.All()
+
+	var names []string
+	metrics.ForEach(func(name string, v metrics.Value) bool {
+		names = append(names, name)
+		return true
+	})
+	if len(names) != len(descs) {
+		t.Fatalf("ForEach visited %d metrics, want %d", len(names), len(descs))
+	}
+	seen := make(map[string]bool, len(names))
+	for i := range descs {
+		seen[names[i]] = true
+		if names[i] != descs[i].Name {
+			t.Errorf("ForEach visited metric %d as %s, want %s", i, names[i], descs[i].Name)
+		}
+	}
+	// Expensive metrics are visited like any other.
+	for _, d := range descs {
+		if !seen[d.Name] {
+			t.Errorf("ForEach did not visit %s", d.Name)
+		}
+	}
+
+	// Make sure iteration stops as soon as fn returns false.
+	const stopAfter = 3
+	n := 0
+	metrics.ForEach(func(name string, v metrics.Value) bool {
+		n++
+		return n < stopAfter
+	})
+	if n != stopAfter {
+		t.Errorf("ForEach visited %d metrics after stopping, want %d", n, stopAfter)
+	}
+}
+
+func histogramSamples() []metrics.Sample {
+	var samples []metrics.Sample
+	for _, d := range metrics.All() {
+		if d.Kind == metrics.KindFloat64Histogram {
+			samples = append(samples, metrics.Sample{Name: d.Name})
+		}
+	}
+	return samples
+}
+
+func TestReadReusesHistograms(t *testing.T) {
+	samples := histogramSamples()
+	metrics.Read(samples)
+	hists := make([]*metrics.Float64Histogram, len(samples))
+	counts := make([]*uint64, len(samples))
+	for i, s := range samples {
+		hists[i] = s.Value.Float64Histogram()
+		counts[i] = &hists[i].Counts[0]
+	}
+
+	metrics.Read(samples)
+	for i, s := range samples {
+		h := s.Value.Float64Histogram()
+		if h != hists[i] {
+			t.Errorf("%s: Read replaced the *Float64Histogram instead of reusing it", s.Name)
+		} else if &h.Counts[0] != counts[i] {
+			t.Errorf("%s: Read replaced the Counts slice instead of reusing it", s.Name)
+		}
+	}
+
+	samples = readAllSamples()
+	if allocs := testing.AllocsPerRun(10, func() { metrics.Read(samples) }); allocs != 0 {
+		t.Errorf("steady-state Read of all metrics allocated %v times, want 0", allocs)
+	}
+}
+
+func BenchmarkReadHistograms(b *testing.B) {
+	samples := histogramSamples()
+	metrics.Read(samples)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		metrics.Read(samples)
+	}
+}
+
+func TestSupported(t *testing.T) {
+	descs := metrics.Supported()
+	if len(descs) == 0 {
+		t.Fatal("Supported returned no metrics")
+	}
+	for _, d := range descs {
+		s := []metrics.Sample{{Name: d.Name}}
+		metrics.Read(s)
+		if k := s[0].Value.Kind(); k != d.Kind {
+			t.Errorf("%s: Read produced a value of kind %v, want %v", d.Name, k, d.Kind)
+		}
+	}
+	if again := metrics.Supported(); len(again) != len(descs) {
+		t.Errorf("second call to Supported returned %d metrics, want %d", len(again), len(descs))
+	}
+}
+
+func TestReadContext(t *testing.T) {
+	const (
+		cheap     = "/sched/goroutines:goroutines"
+		expensive = "/sched/goroutines/stack-size:bytes"
+	)
+	samples := []metrics.Sample{{Name: cheap}, {Name: expensive}, {Name: "/gc/cycles/total:gc-cycles"}}
+
+	if err := metrics.ReadContext(context.Background(), samples); err != nil {
+		t.Fatalf("ReadContext with a live context: %v", err)
+	}
+	for _, s := range samples {
+		if s.Value.Kind() == metrics.KindBad {
+			t.Errorf("ReadContext with a live context did not populate %s", s.Name)
+		}
+	}
+
+	samples = []metrics.Sample{{Name: cheap}, {Name: expensive}, {Name: "/gc/cycles/total:gc-cycles"}}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := metrics.ReadContext(ctx, samples); err != context.Canceled {
+		t.Errorf("ReadContext with a canceled context returned %v, want %v", err, context.Canceled)
+	}
+	for _, i := range []int{0, 2} {
+		if samples[i].Value.Kind() != metrics.KindUint64 {
+			t.Errorf("ReadContext with a canceled context did not populate cheap metric %s", samples[i].Name)
+		}
+	}
+	if k := samples[1].Value.Kind(); k != metrics.KindBad {
+		t.Errorf("ReadContext with a canceled context populated expensive metric %s as kind %d", expensive, k)
+	}
+
+	if err := metrics.ReadContext(context.Background(), nil); err != nil {
+		t.Errorf("ReadContext with no samples: %v", err)
+	}
+}
+
+func TestNewSamples(t *testing.T) {
+	all := metrics.All()
+	samples, err := metrics.NewSamples()
+	if err != nil {
+		t.Fatalf("NewSamples(): %v", err)
+	}
+	if len(samples) != len(all) {
+		t.Fatalf("NewSamples() returned %d samples, want %d", len(samples), len(all))
+	}
+	for i := range samples {
+		if samples[i].Name != all[i].Name {
+			t.Errorf("NewSamples() sample %d is %s, want %s", i, samples[i].Name, all[i].Name)
+		}
+		if samples[i].Value.Kind() != metrics.KindBad {
+			t.Errorf("NewSamples() sample %d has a non-zero Value", i)
+		}
+	}
+
+	names := []string{"/sched/goroutines:goroutines", "/gc/cycles/total:gc-cycles"}
+	samples, err = metrics.NewSamples(names...)
+	if err != nil {
+		t.Fatalf("NewSamples(%q): %v", names, err)
+	}
+	if len(samples) != len(names) {
+		t.Fatalf("NewSamples(%q) returned %d samples, want %d", names, len(samples), len(names))
+	}
+	for i := range samples {
+		if samples[i].Name != names[i] {
+			t.Errorf("NewSamples(%q) sample %d is %s, want %s", names, i, samples[i].Name, names[i])
+		}
+	}
+	metrics.Read(samples)
+	for _, s := range samples {
+		if s.Value.Kind() != metrics.KindUint64 {
+			t.Errorf("reading %s from NewSamples gave kind %d", s.Name, s.Value.Kind())
+		}
+	}
+
+	if _, err := metrics.NewSamples("/sched/goroutines:goroutines", "/not/a/metric:bytes"); err == nil {
+		t.Error("NewSamples with an unknown name succeeded")
+	}
+}
@@ -24,6 +24,9 @@ const (
 
 	// KindFloat64Histogram indicates that the type of the Value is a *Float64Histogram.
 	KindFloat64Histogram
+
+	// KindString indicates that the type of the Value is a string.
+	KindString
 )
 
 // Value represents a metric value returned by the runtime.
@@ -38,6 +41,36 @@ func (v Value) Kind() ValueKind {
 	return v.kind
 }
 
+// IsZero reports whether v holds no information, which is the case if:
+//
+//   - v.Kind() is KindBad, as for the zero Value or a Value for an
+//     unsupported metric;
+//   - v.Kind() is KindUint64 or KindFloat64 and the value is zero;
+//   - v.Kind() is KindFloat64Histogram and the histogram is nil or all its
+//     counts are zero, regardless of its buckets;
+//   - v.Kind() is KindString and the string is empty.
+func (v Value) IsZero() bool {
+	switch v.kind {
+	case KindUint64:
+		return v.scalar == 0
+	case KindFloat64:
+		return math.Float64frombits(v.scalar) == 0
+	case KindFloat64Histogram:
+		if v.pointer == nil {
+			return true
+		}
+		for _, c := range (*Float64Histogram)(v.pointer).Counts {
+			if c != 0 {
+				return false
+			}
+		}
+		return true
+	case KindString:
+		return v.pointer == nil || *(*string)(v.pointer) == ""
+	}
+	return true
+}
+
 // Uint64 returns the internal uint64 value for the metric.
 //
 // If v.Kind() != KindUint64, this method panics.
@@ -48,6 +81,16 @@ func (v Value) Uint64() uint64 {
 	return v.scalar
 }
 
+// Uint64Unchecked returns the internal uint64 value for the metric
+// without checking the Value's kind.
+//
+// It is a fast path for callers that have already validated the kind
+// of the metric against its Description. If v.Kind() != KindUint64,
+// the result is meaningless. Most callers should use Uint64 instead.
+func (v Value) Uint64Unchecked() uint64 {
+	return v.scalar
+}
+
 // Float64 returns the internal float64 value for the metric.
 //
 // If v.Kind() != KindFloat64, this method panics.
@@ -58,6 +101,32 @@ func (v Value) Float64() float64 {
 	return math.Float64frombits(v.scalar)
 }
 
+// Float64Unchecked returns the internal float64 value for the metric
+// without checking the Value's kind.
+//
+// It is a fast path for callers that have already validated the kind
+// of the metric against its Description. If v.Kind() != KindFloat64,
+// the result is meaningless. Most callers should use Float64 instead.
+func (v Value) Float64Unchecked() float64 {
+	return math.Float64frombits(v.scalar)
+}
+
+// AsFloat64 returns the value of a scalar metric as a float64, converting
+// it from a uint64 if v.Kind() is KindUint64, and reports whether v is a
+// scalar. For any other Kind, AsFloat64 returns 0, false.
+//
+// A float64 represents integers exactly only up to 2^53, so uint64 values
+// larger than that are rounded to the nearest representable float64.
+func (v Value) AsFloat64() (float64, bool) {
+	switch v.kind {
+	case KindUint64:
+		return float64(v.scalar), true
+	case KindFloat64:
+		return math.Float64frombits(v.scalar), true
+	}
+	return 0, false
+}
+
 // Float64Histogram returns the internal *Float64Histogram value for the metric.
 //
 // If v.Kind() != KindFloat64Histogram, this method panics.
@@ -67,3 +136,13 @@ func (v Value) Float64Histogram() *Float64Histogram {
 	}
 	return (*Float64Histogram)(v.pointer)
 }
+
+// StringValue returns the internal string value for the metric.
+//
+// If v.Kind() != KindString, this method panics.
+func (v Value) StringValue() string {
+	if v.kind != KindString {
+		panic("called StringValue on non-string metric value")
+	}
+	return *(*string)(v.pointer)
+}
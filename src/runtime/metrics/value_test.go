@@ -0,0 +1,148 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package metrics_test
+
+import (
+	"runtime"
+	"runtime/metrics"
+	"testing"
+)
+
+func readAllSamples() []metrics.Sample {
+	descs := metrics.All()
+	samples := make([]metrics.Sample, len(descs))
+	for i := range samples {
+		samples[i].Name = descs[i].Name
+	}
+	metrics.Read(samples)
+	return samples
+}
+
+func TestValueUnchecked(t *testing.T) {
+	for _, s := range readAllSamples() {
+		switch s.Value.Kind() {
+		case metrics.KindUint64:
+			if got, want := s.Value.Uint64Unchecked(), s.Value.Uint64(); got != want {
+				t.Errorf("%s: Uint64Unchecked returned %d, want %d", s.Name, got, want)
+			}
+		case metrics.KindFloat64:
+			if got, want := s.Value.Float64Unchecked(), s.Value.Float64(); got != want {
+				t.Errorf("%s: Float64Unchecked returned %f, want %f", s.Name, got, want)
+			}
+		}
+	}
+}
+
+func TestValueString(t *testing.T) {
+	s := []metrics.Sample{
+		{Name: "/build/version:string"},
+		{Name: "/sched/goroutines:goroutines"},
+	}
+	metrics.Read(s)
+	if k := s[0].Value.Kind(); k != metrics.KindString {
+		t.Fatalf("/build/version:string has kind %v, want %v", k, metrics.KindString)
+	}
+	if got, want := s[0].Value.StringValue(), runtime.Version(); got != want {
+		t.Errorf("/build/version:string = %q, want %q", got, want)
+	}
+
+	mustPanic := func(name string, f func()) {
+		t.Helper()
+		defer func() {
+			if recover() == nil {
+				t.Errorf("%s did not panic", name)
+			}
+		}()
+		f()
+	}
+	mustPanic("StringValue on uint64 value", func() { s[1].Value.StringValue() })
+	mustPanic("Uint64 on string value", func() { s[0].Value.Uint64() })
+}
+
+var valueSink uint64
+
+func BenchmarkValueUint64(b *testing.B) {
+	samples := readAllSamples()
+	b.Run("Checked", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for j := range samples {
+				if samples[j].Value.Kind() == metrics.KindUint64 {
+					valueSink += samples[j].Value.Uint64()
+				}
+			}
+		}
+	})
+	b.Run("Unchecked", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for j := range samples {
+				if samples[j].Value.Kind() == metrics.KindUint64 {
+					valueSink += samples[j].Value.Uint64Unchecked()
+				}
+			}
+		}
+	})
+}
+
+func TestValueIsZero(t *testing.T) {
+	buckets := []float64{0, 1, 2}
+	for _, tc := range []struct {
+		name string
+		v    metrics.Value
+		want bool
+	}{
+		{"KindBad", metrics.Value{}, true},
+		{"zero uint64", metrics.NewUint64Value(0), true},
+		{"non-zero uint64", metrics.NewUint64Value(7), false},
+		{"zero float64", metrics.NewFloat64Value(0), true},
+		{"non-zero float64", metrics.NewFloat64Value(0.5), false},
+		{"nil histogram", metrics.NewFloat64HistogramValue(nil), true},
+		{
+			"empty histogram",
+			metrics.NewFloat64HistogramValue(&metrics.Float64Histogram{Counts: []uint64{0, 0}, Buckets: buckets}),
+			true,
+		},
+		{
+			"non-empty histogram",
+			metrics.NewFloat64HistogramValue(&metrics.Float64Histogram{Counts: []uint64{0, 3}, Buckets: buckets}),
+			false,
+		},
+	} {
+		if got := tc.v.IsZero(); got != tc.want {
+			t.Errorf("IsZero for %s = %t, want %t", tc.name, got, tc.want)
+		}
+	}
+
+	// Strings only come from Read.
+	s := []metrics.Sample{{Name: "/build/version:string"}}
+	metrics.Read(s)
+	if s[0].Value.IsZero() {
+		t.Error("IsZero for /build/version:string = true, want false")
+	}
+}
+
+func TestValueAsFloat64(t *testing.T) {
+	buckets := []float64{0, 1, 2}
+	for _, tc := range []struct {
+		name   string
+		v      metrics.Value
+		want   float64
+		wantOK bool
+	}{
+		{"uint64", metrics.NewUint64Value(42), 42, true},
+		{"large uint64", metrics.NewUint64Value(1<<53 + 1), 1 << 53, true},
+		{"float64", metrics.NewFloat64Value(0.25), 0.25, true},
+		{
+			"histogram",
+			metrics.NewFloat64HistogramValue(&metrics.Float64Histogram{Counts: []uint64{1, 2}, Buckets: buckets}),
+			0,
+			false,
+		},
+		{"KindBad", metrics.Value{}, 0, false},
+	} {
+		if got, ok := tc.v.AsFloat64(); got != tc.want || ok != tc.wantOK {
+			t.Errorf("AsFloat64 for %s = %v, %t; want %v, %t", tc.name, got, ok, tc.want, tc.wantOK)
+		}
+	}
+}
@@ -5,11 +5,16 @@
 package runtime_test
 
 import (
+	"fmt"
+	"math"
+	"reflect"
 	"runtime"
+	"runtime/debug"
 	"runtime/metrics"
 	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 	"unsafe"
@@ -44,6 +49,7 @@ func TestReadMetrics(t *testing.T) {
 	var allocsBySize *metrics.Float64Histogram
 	var tinyAllocs uint64
 	var mallocs, frees uint64
+	var typesSys uint64
 	for i := range samples {
 		switch name := samples[i].Name; name {
 		case "/memory/classes/heap/free:bytes":
@@ -66,10 +72,14 @@ func TestReadMetrics(t *testing.T) {
 			checkUint64(t, name, samples[i].Value.Uint64(), mstats.MSpanInuse)
 		case "/memory/classes/metadata/other:bytes":
 			checkUint64(t, name, samples[i].Value.Uint64(), mstats.GCSys)
+		case "/memory/classes/metadata/types:bytes":
+			// Included in MemStats.OtherSys. Sorts before
+			// /memory/classes/other:bytes, which is checked below.
+			typesSys = samples[i].Value.Uint64()
 		case "/memory/classes/os-stacks:bytes":
 			checkUint64(t, name, samples[i].Value.Uint64(), mstats.StackSys-mstats.StackInuse)
 		case "/memory/classes/other:bytes":
-			checkUint64(t, name, samples[i].Value.Uint64(), mstats.OtherSys)
+			checkUint64(t, name, samples[i].Value.Uint64(), mstats.OtherSys-typesSys)
 		case "/memory/classes/profiling/buckets:bytes":
 			checkUint64(t, name, samples[i].Value.Uint64(), mstats.BuckHashSys)
 		case "/memory/classes/total:bytes":
@@ -405,3 +415,1753 @@ func TestReadMetricsCumulative(t *testing.T) {
 
 	wg.Wait()
 }
+
+// readMetric reads a single metric by name and fails the test
+// if it is not supported.
+func readMetric(t *testing.T, name string) metrics.Value {
+	t.Helper()
+	s := []metrics.Sample{{Name: name}}
+	metrics.Read(s)
+	if s[0].Value.Kind() == metrics.KindBad {
+		t.Fatalf("metric %q is not supported", name)
+	}
+	return s[0].Value
+}
+
+func TestReadMetricsMSpanCount(t *testing.T) {
+	count := readMetric(t, "/memory/metadata/mspan/count:objects").Uint64()
+	if count == 0 {
+		t.Fatal("no mspan structures are allocated in a running program")
+	}
+	inuse := readMetric(t, "/memory/classes/metadata/mspan/inuse:bytes").Uint64()
+	if inuse < count {
+		t.Errorf("mspan inuse bytes %d is smaller than the mspan count %d", inuse, count)
+	}
+}
+
+// ItabBase is embedded in the struct types built by TestReadMetricsTypes
+// to give them a method.
+type ItabBase struct{ A, B uintptr }
+
+func (ItabBase) ItabMethod() {}
+
+// itabTypes counts the types built by TestReadMetricsTypes, which must
+// be new each time the test runs.
+var itabTypes int
+
+func TestReadMetricsTypes(t *testing.T) {
+	before := readMetric(t, "/memory/classes/metadata/types:bytes").Uint64()
+
+	// Build many distinct types with a method, and convert each of them
+	// to an interface. The compiler can't know about these types, so the
+	// runtime has to build an itab for each of them.
+	const n = 1000
+	for i := 0; i < n; i++ {
+		typ := reflect.StructOf([]reflect.StructField{
+			{Name: "ItabBase", Type: reflect.TypeOf(ItabBase{}), Anonymous: true},
+			{Name: fmt.Sprintf("F%d", itabTypes), Type: reflect.TypeOf(0)},
+		})
+		itabTypes++
+		v := reflect.New(typ).Elem().Interface()
+		if _, ok := v.(interface{ ItabMethod() }); !ok {
+			t.Fatalf("%v does not have method ItabMethod", typ)
+		}
+	}
+
+	after := readMetric(t, "/memory/classes/metadata/types:bytes").Uint64()
+	if after < before+n*uint64(unsafe.Sizeof(uintptr(0))) {
+		t.Errorf("type metadata went from %d to %d bytes after building %d itabs", before, after, n)
+	}
+}
+
+func TestReadMetricsMCacheCount(t *testing.T) {
+	for _, procs := range []int{runtime.GOMAXPROCS(0), 4} {
+		old := runtime.GOMAXPROCS(procs)
+		count := readMetric(t, "/memory/metadata/mcache/count:objects").Uint64()
+		runtime.GOMAXPROCS(old)
+		if count < uint64(procs) {
+			t.Errorf("GOMAXPROCS=%d: only %d mcache structures are allocated", procs, count)
+		}
+	}
+}
+
+var gcAssistSink []*[64]*int
+
+// driveGCAssists allocates heavily until progress returns true,
+// reporting whether it did so before giving up.
+//
+// Assists happen when goroutines allocate faster than the GC can mark,
+// so this builds up a pointer-heavy live heap for the GC to chew on, then
+// allocates as fast as possible from several goroutines.
+func driveGCAssists(progress func() bool) bool {
+	defer func() { gcAssistSink = nil }()
+	for i := 0; i < 1<<14; i++ {
+		var x [64]*int
+		for j := range x {
+			x[j] = new(int)
+		}
+		gcAssistSink = append(gcAssistSink, &x)
+	}
+
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		var wg sync.WaitGroup
+		sinks := make([][]*int, 2*runtime.GOMAXPROCS(-1))
+		for i := range sinks {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				for j := 0; j < 1<<17; j++ {
+					sinks[i] = make([]*int, 64)
+				}
+			}(i)
+		}
+		wg.Wait()
+		if progress() {
+			return true
+		}
+	}
+	return false
+}
+
+func TestReadMetricsGCAssistTime(t *testing.T) {
+	before := readMetric(t, "/gc/assist/time:seconds").Float64()
+	if !driveGCAssists(func() bool {
+		return readMetric(t, "/gc/assist/time:seconds").Float64() > before
+	}) {
+		t.Error("/gc/assist/time:seconds did not increase under heavy allocation")
+	}
+}
+
+func TestReadMetricsGCCPUClasses(t *testing.T) {
+	names := []string{
+		"/cpu/classes/gc/mark/assist:cpu-seconds",
+		"/cpu/classes/gc/mark/dedicated:cpu-seconds",
+		"/cpu/classes/gc/pause:cpu-seconds",
+		"/gc/assist/time:seconds",
+	}
+	read := func() []float64 {
+		s := make([]metrics.Sample, len(names))
+		for i := range s {
+			s[i].Name = names[i]
+		}
+		metrics.Read(s)
+		v := make([]float64, len(s))
+		for i := range s {
+			v[i] = s[i].Value.Float64()
+		}
+		return v
+	}
+	before := read()
+	if !driveGCAssists(func() bool {
+		return read()[0] > before[0]
+	}) {
+		t.Errorf("%s did not increase under heavy allocation", names[0])
+	}
+	runtime.GC()
+	after := read()
+	for i, name := range names {
+		if after[i] < 0 {
+			t.Errorf("%s is negative: %f", name, after[i])
+		}
+		if after[i] < before[i] {
+			t.Errorf("%s decreased: %f < %f", name, after[i], before[i])
+		}
+	}
+	if after[2] <= before[2] {
+		t.Errorf("%s did not increase after a GC", names[2])
+	}
+	if after[0] != after[3] {
+		t.Errorf("%s = %f, want the same as %s = %f", names[0], after[0], names[3], after[3])
+	}
+}
+
+var scavengeSink []byte
+
+func TestReadMetricsScavengeReleased(t *testing.T) {
+	before := readMetric(t, "/gc/scavenge/released:bytes").Uint64()
+
+	// Create some free heap memory and force the runtime
+	// to return it to the underlying system.
+	scavengeSink = make([]byte, 64<<20)
+	scavengeSink = nil
+	debug.FreeOSMemory()
+
+	after := readMetric(t, "/gc/scavenge/released:bytes").Uint64()
+	if after <= before {
+		t.Errorf("/gc/scavenge/released:bytes did not increase after FreeOSMemory: before %d, after %d", before, after)
+	}
+}
+
+//go:noinline
+func deepRecurse(n int) int {
+	var buf [128]byte
+	if n == 0 {
+		return int(buf[0])
+	}
+	return deepRecurse(n-1) + int(buf[n%len(buf)])
+}
+
+func TestReadMetricsStackGrowths(t *testing.T) {
+	before := readMetric(t, "/gc/stack/growths:operations").Uint64()
+
+	// Each of these goroutines starts with a small stack and
+	// must grow it at least once to recurse this deeply.
+	const n = 10
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			deepRecurse(1 << 10)
+		}()
+	}
+	wg.Wait()
+
+	after := readMetric(t, "/gc/stack/growths:operations").Uint64()
+	if after < before+n {
+		t.Errorf("/gc/stack/growths:operations advanced by %d, want at least %d", after-before, n)
+	}
+}
+
+func TestReadMetricsStackShrinks(t *testing.T) {
+	before := readMetric(t, "/gc/stack/shrinks:operations").Uint64()
+
+	// Create goroutines which grow their stacks considerably,
+	// then park with very little of the stack in use, so the
+	// GC can shrink them.
+	const n = 10
+	var ready sync.WaitGroup
+	done := make(chan struct{})
+	var exited sync.WaitGroup
+	for i := 0; i < n; i++ {
+		ready.Add(1)
+		exited.Add(1)
+		go func() {
+			defer exited.Done()
+			deepRecurse(1 << 10)
+			ready.Done()
+			<-done
+		}()
+	}
+	ready.Wait()
+	runtime.GC()
+	runtime.GC()
+	close(done)
+	exited.Wait()
+
+	after := readMetric(t, "/gc/stack/shrinks:operations").Uint64()
+	if after < before+n {
+		t.Errorf("/gc/stack/shrinks:operations advanced by %d, want at least %d", after-before, n)
+	}
+}
+
+func TestReadMetricsLastCycleAge(t *testing.T) {
+	runtime.GC()
+	first := readMetric(t, "/gc/last-cycle/age:seconds").Float64()
+	if first < 0 || first > 1 {
+		t.Errorf("/gc/last-cycle/age:seconds is %f immediately after a GC", first)
+	}
+	time.Sleep(50 * time.Millisecond)
+	second := readMetric(t, "/gc/last-cycle/age:seconds").Float64()
+	if second <= first {
+		t.Errorf("/gc/last-cycle/age:seconds did not grow: first %f, second %f", first, second)
+	}
+}
+
+var (
+	ifaceAllocSink any
+	largeAllocSink []byte
+)
+
+func TestReadMetricsInterfaceAllocs(t *testing.T) {
+	const n = 100
+
+	// Interface allocation counts are flushed lazily, but
+	// always by the time a GC cycle has completed.
+	runtime.GC()
+	before := readMetric(t, "/gc/heap/allocs/reason/interface:objects").Uint64()
+	for i := 0; i < n; i++ {
+		// Use values too big to be served from the runtime's
+		// table of small integers.
+		ifaceAllocSink = uint64(1000 + i)
+	}
+	ifaceAllocSink = nil
+	runtime.GC()
+	after := readMetric(t, "/gc/heap/allocs/reason/interface:objects").Uint64()
+	if after-before < n {
+		t.Errorf("interface allocation count advanced by %d, want at least %d", after-before, n)
+	}
+}
+
+func TestReadMetricsArenaReserved(t *testing.T) {
+	reserved := readMetric(t, "/memory/heap/arenas/reserved:bytes").Uint64()
+	if reserved == 0 {
+		t.Fatal("no heap arena address space is reserved in a running program")
+	}
+	if reserved%uint64(runtime.PhysPageSize) != 0 {
+		t.Errorf("reserved arena address space %d is not a multiple of the physical page size %d", reserved, runtime.PhysPageSize)
+	}
+	if objects := readMetric(t, "/memory/classes/heap/objects:bytes").Uint64(); reserved < objects {
+		t.Errorf("reserved arena address space %d is smaller than heap objects %d", reserved, objects)
+	}
+}
+
+func TestReadMetricsSTW(t *testing.T) {
+	events := readMetric(t, "/sched/stw/events:events").Uint64()
+	total := readMetric(t, "/sched/stw/total:seconds").Float64()
+
+	// ReadMemStats stops the world each time it's called.
+	const n = 5
+	var mstats runtime.MemStats
+	for i := 0; i < n; i++ {
+		runtime.ReadMemStats(&mstats)
+	}
+
+	if got := readMetric(t, "/sched/stw/events:events").Uint64(); got-events < n {
+		t.Errorf("/sched/stw/events:events advanced by %d, want at least %d", got-events, n)
+	}
+	if got := readMetric(t, "/sched/stw/total:seconds").Float64(); got <= total {
+		t.Errorf("/sched/stw/total:seconds did not increase: before %f, after %f", total, got)
+	}
+}
+
+func TestReadMetricsGOMAXPROCSChanges(t *testing.T) {
+	procs := runtime.GOMAXPROCS(-1)
+	defer runtime.GOMAXPROCS(procs)
+
+	before := readMetric(t, "/sched/gomaxprocs/changes:events").Uint64()
+	runtime.GOMAXPROCS(procs + 1)
+	runtime.GOMAXPROCS(procs + 1)
+	after := readMetric(t, "/sched/gomaxprocs/changes:events").Uint64()
+	if after-before != 1 {
+		t.Errorf("/sched/gomaxprocs/changes:events advanced by %d, want 1", after-before)
+	}
+}
+
+// parkAtDepth recurses n times, then signals ready and blocks
+// until done is closed.
+//
+//go:noinline
+func parkAtDepth(n int, ready *sync.WaitGroup, done chan struct{}) int {
+	var buf [128]byte
+	if n == 0 {
+		ready.Done()
+		<-done
+		return int(buf[0])
+	}
+	return parkAtDepth(n-1, ready, done) + int(buf[n%len(buf)])
+}
+
+func TestReadMetricsGoroutineStackSizes(t *testing.T) {
+	var ready, exited sync.WaitGroup
+	done := make(chan struct{})
+	for _, depth := range []int{0, 1 << 4, 1 << 8, 1 << 10, 1 << 12} {
+		for i := 0; i < 4; i++ {
+			ready.Add(1)
+			exited.Add(1)
+			go func(depth int) {
+				defer exited.Done()
+				parkAtDepth(depth, &ready, done)
+			}(depth)
+		}
+	}
+	ready.Wait()
+	defer func() {
+		close(done)
+		exited.Wait()
+	}()
+
+	s := []metrics.Sample{
+		{Name: "/sched/goroutines:goroutines"},
+		{Name: "/sched/goroutines/stack-size:bytes"},
+	}
+	metrics.Read(s)
+	goroutines := s[0].Value.Uint64()
+	hist := s[1].Value.Float64Histogram()
+
+	var total uint64
+	largest := 0.0
+	for i, c := range hist.Counts {
+		total += c
+		if c != 0 {
+			largest = hist.Buckets[i]
+		}
+	}
+	if total != goroutines {
+		t.Errorf("stack size histogram has %d samples, want %d (the live goroutine count)", total, goroutines)
+	}
+	// The deepest goroutines use well over 64 KiB of stack.
+	if largest < 64<<10 {
+		t.Errorf("largest non-empty stack size bucket starts at %v bytes, want at least %d", largest, 64<<10)
+	}
+}
+
+func TestReadMetricsLastCycleFrees(t *testing.T) {
+	// Allocate a known number of objects and make sure they survive
+	// one GC, so that the next GC frees them.
+	const n = 1 << 16
+	objs := make([]*[8]int, n)
+	for i := range objs {
+		objs[i] = new([8]int)
+	}
+	runtime.GC()
+	runtime.KeepAlive(objs)
+	objs = nil
+	runtime.GC()
+
+	// Allow for a small amount of additional garbage created by
+	// the test framework and other background goroutines.
+	freed := readMetric(t, "/gc/heap/frees/last-cycle:objects").Uint64()
+	if freed < n || freed > n+n/10 {
+		t.Errorf("/gc/heap/frees/last-cycle:objects = %d, want approximately %d", freed, n)
+	}
+}
+
+type finRetainedNode struct {
+	child *[8]int
+	_     [8]int
+}
+
+func TestReadMetricsFinalizersRetained(t *testing.T) {
+	// Build a graph of objects with finalizers, each referencing
+	// another object without one, then drop it.
+	const n = 1 << 10
+	var finalized atomic.Int64
+	nodes := make([]*finRetainedNode, n)
+	for i := range nodes {
+		nodes[i] = &finRetainedNode{child: new([8]int)}
+		runtime.SetFinalizer(nodes[i], func(*finRetainedNode) { finalized.Add(1) })
+	}
+	runtime.GC()
+	runtime.KeepAlive(nodes)
+	nodes = nil
+
+	// The next GC finds the nodes unreachable, but keeps them alive for
+	// their finalizers. Other tests may leave objects with finalizers
+	// around too, so only check for a lower bound.
+	runtime.GC()
+	if retained := readMetric(t, "/gc/finalizers/retained:objects").Uint64(); retained < n {
+		t.Errorf("/gc/finalizers/retained:objects = %d after dropping %d objects with finalizers", retained, n)
+	}
+
+	// Once the finalizers have run, the nodes are ordinary garbage.
+	for deadline := time.Now().Add(5 * time.Second); finalized.Load() < n && time.Now().Before(deadline); {
+		time.Sleep(time.Millisecond)
+	}
+	if got := finalized.Load(); got < n {
+		t.Fatalf("only %d of %d finalizers ran", got, n)
+	}
+	runtime.GC()
+	if retained := readMetric(t, "/gc/finalizers/retained:objects").Uint64(); retained >= n {
+		t.Errorf("/gc/finalizers/retained:objects = %d after all %d finalizers ran", retained, n)
+	}
+}
+
+func TestReadMetricsWriteBarrierShades(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+
+	// A large pointer-heavy structure keeps each GC cycle marking for a
+	// while, during which the loop below keeps writing pointers to fresh,
+	// unmarked objects into it.
+	ptrs := make([]*[4]int, 1<<20)
+	for i := range ptrs {
+		ptrs[i] = new([4]int)
+	}
+	before := readMetric(t, "/gc/write-barrier/shades:operations").Uint64()
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				runtime.GC()
+			}
+		}
+	}()
+	after := before
+	for i := 0; i < 1<<10 && after == before; i++ {
+		for j := range ptrs {
+			ptrs[j], ptrs[len(ptrs)-1-j] = ptrs[len(ptrs)-1-j], new([4]int)
+		}
+		after = readMetric(t, "/gc/write-barrier/shades:operations").Uint64()
+	}
+	close(done)
+	wg.Wait()
+	runtime.KeepAlive(ptrs)
+
+	if after == before {
+		t.Errorf("/gc/write-barrier/shades:operations did not advance while mutating the heap during GC: stayed at %d", before)
+	}
+}
+
+var heapGrowthSink []byte
+
+// heapGrowthSize is the size of the allocation tests use to force the
+// heap to grow. It spans at least one whole heap arena on every platform.
+const heapGrowthSize = 64 << 20
+
+// skipUnlessHeapMustGrow skips the test if the heap may be able to
+// satisfy an allocation of heapGrowthSize bytes without growing.
+func skipUnlessHeapMustGrow(t *testing.T) {
+	s := []metrics.Sample{
+		{Name: "/memory/classes/heap/free:bytes"},
+		{Name: "/memory/classes/heap/released:bytes"},
+	}
+	metrics.Read(s)
+	if free := s[0].Value.Uint64() + s[1].Value.Uint64(); free >= heapGrowthSize {
+		t.Skipf("heap already has %d free bytes", free)
+	}
+}
+
+func TestReadMetricsMmapCalls(t *testing.T) {
+	skipUnlessHeapMustGrow(t)
+	before := readMetric(t, "/memory/sys/mmap-calls:calls").Uint64()
+	heapGrowthSink = make([]byte, heapGrowthSize)
+	heapGrowthSink = nil
+
+	after := readMetric(t, "/memory/sys/mmap-calls:calls").Uint64()
+	if after <= before {
+		t.Errorf("/memory/sys/mmap-calls:calls did not increase after heap growth: before %d, after %d", before, after)
+	}
+}
+
+func TestReadMetricsLargestAlloc(t *testing.T) {
+	const size = 100<<20 + 1
+	largeAllocSink = make([]byte, size)
+	largeAllocSink = nil
+
+	if got := readMetric(t, "/gc/heap/allocs/largest:bytes").Uint64(); got < size {
+		t.Errorf("/gc/heap/allocs/largest:bytes = %d, want at least %d", got, size)
+	}
+
+	// The high-water mark must survive the destruction of the P,
+	// and thus the mcache, that recorded it.
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(1))
+	if got := readMetric(t, "/gc/heap/allocs/largest:bytes").Uint64(); got < size {
+		t.Errorf("/gc/heap/allocs/largest:bytes = %d after reducing GOMAXPROCS, want at least %d", got, size)
+	}
+}
+
+func TestReadMetricsIdleTransitions(t *testing.T) {
+	for i := 0; i < 3; i++ {
+		// Finish any GC cycle left over from earlier tests, whose
+		// mark workers would otherwise keep idle Ps busy.
+		runtime.GC()
+		before := readMetric(t, "/sched/procs/idle-transitions:events").Uint64()
+
+		// Keep every P busy for a little while, so that each has to
+		// leave the idle list, ...
+		var wg sync.WaitGroup
+		stop := time.Now().Add(5 * time.Millisecond)
+		for j := 0; j < runtime.GOMAXPROCS(-1); j++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for time.Now().Before(stop) {
+				}
+			}()
+		}
+		wg.Wait()
+
+		// ... then leave them with nothing to do, so that they go
+		// back on it.
+		time.Sleep(10 * time.Millisecond)
+		after := readMetric(t, "/sched/procs/idle-transitions:events").Uint64()
+		if after <= before {
+			t.Errorf("/sched/procs/idle-transitions:events did not increase while idle: before %d, after %d", before, after)
+		}
+	}
+}
+
+func TestReadMetricsOSStacks(t *testing.T) {
+	// Even without cgo, the main thread runs on an OS stack.
+	if n := readMetric(t, "/memory/os-stacks:objects").Uint64(); n == 0 {
+		t.Error("/memory/os-stacks:objects is zero")
+	}
+}
+
+//go:noinline
+func deferInLoop(n int) {
+	for i := 0; i < n; i++ {
+		// Defers in loops are heap-allocated from the defer pools.
+		defer func() {}()
+	}
+}
+
+func TestReadMetricsDeferPool(t *testing.T) {
+	// A GC empties the defer pools, so allow for one happening
+	// between filling them and reading the metric.
+	for i := 0; i < 3; i++ {
+		deferInLoop(100)
+		if readMetric(t, "/sched/defers/pooled:objects").Uint64() > 0 {
+			return
+		}
+	}
+	t.Error("/sched/defers/pooled:objects is zero after running heap-allocated defers")
+}
+
+var allocRateSink []byte
+
+func TestReadMetricsAllocRate(t *testing.T) {
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 1<<12; j++ {
+			allocRateSink = make([]byte, 1<<10)
+		}
+		runtime.GC()
+	}
+	allocRateSink = nil
+
+	rate := readMetric(t, "/gc/pacer/alloc-rate:bytes/second").Float64()
+	if rate <= 0 || math.IsInf(rate, 0) || math.IsNaN(rate) {
+		t.Errorf("/gc/pacer/alloc-rate:bytes/second = %f, want a positive, finite value", rate)
+	}
+}
+
+func TestReadMetricsParkedThreads(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+
+	// Start a burst of CPU-bound work that occupies every P, and so
+	// runs on several threads, then let it subside.
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for start := time.Now(); time.Since(start) < 20*time.Millisecond; {
+			}
+		}()
+	}
+	wg.Wait()
+
+	// The threads that ran the burst have nothing left to do, so
+	// some of them should park.
+	var n uint64
+	for deadline := time.Now().Add(time.Second); n == 0 && time.Now().Before(deadline); {
+		time.Sleep(time.Millisecond)
+		n = readMetric(t, "/sched/threads/parked:threads").Uint64()
+	}
+	if n == 0 {
+		t.Error("/sched/threads/parked:threads stayed zero after a burst of work")
+	}
+}
+
+func TestReadMetricsSpinningThreads(t *testing.T) {
+	procs := uint64(runtime.GOMAXPROCS(-1))
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	// Generate bursts of short-lived goroutines, which wake
+	// spinning threads to find them.
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			var burst sync.WaitGroup
+			for i := 0; i < 10; i++ {
+				burst.Add(1)
+				go burst.Done()
+			}
+			burst.Wait()
+			time.Sleep(100 * time.Microsecond)
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		if n := readMetric(t, "/sched/threads/spinning:threads").Uint64(); n > procs {
+			t.Errorf("/sched/threads/spinning:threads = %d, want at most GOMAXPROCS (%d)", n, procs)
+		}
+	}
+	close(done)
+	wg.Wait()
+}
+
+var scanSink []*[32]*int
+
+func TestReadMetricsScanTotal(t *testing.T) {
+	// Build a pointer-dense live heap of at least heapSize bytes,
+	// every byte of which the GC must scan.
+	const heapSize = 16 << 20
+	scanSink = make([]*[32]*int, heapSize/unsafe.Sizeof([32]*int{}))
+	for i := range scanSink {
+		x := new([32]*int)
+		for j := range x {
+			x[j] = new(int)
+		}
+		scanSink[i] = x
+	}
+	defer func() { scanSink = nil }()
+
+	runtime.GC()
+	before := readMetric(t, "/gc/scan/total:bytes").Uint64()
+	const cycles = 3
+	for i := 0; i < cycles; i++ {
+		runtime.GC()
+	}
+	after := readMetric(t, "/gc/scan/total:bytes").Uint64()
+	if after-before < cycles*heapSize {
+		t.Errorf("/gc/scan/total:bytes advanced by %d over %d cycles, want at least %d", after-before, cycles, cycles*heapSize)
+	}
+}
+
+var (
+	noscanSink    []byte
+	scanSliceSink []*int
+)
+
+func TestReadMetricsScannableHeap(t *testing.T) {
+	defer func() {
+		noscanSink = nil
+		scanSliceSink = nil
+	}()
+	// The scannable heap estimate is reset from the scan work of each
+	// cycle, so measure it after a GC.
+	const size = 32 << 20
+	runtime.GC()
+	base := readMetric(t, "/gc/heap/scannable:bytes").Uint64()
+
+	noscanSink = make([]byte, size)
+	runtime.GC()
+	afterNoscan := readMetric(t, "/gc/heap/scannable:bytes").Uint64()
+
+	scanSliceSink = make([]*int, size/unsafe.Sizeof((*int)(nil)))
+	runtime.GC()
+	afterScan := readMetric(t, "/gc/heap/scannable:bytes").Uint64()
+
+	noscanDelta := int64(afterNoscan) - int64(base)
+	scanDelta := int64(afterScan) - int64(afterNoscan)
+	if scanDelta < size/2 {
+		t.Errorf("/gc/heap/scannable:bytes grew by %d after allocating %d bytes of pointers, want at least %d", scanDelta, size, size/2)
+	}
+	if noscanDelta >= scanDelta {
+		t.Errorf("/gc/heap/scannable:bytes grew by %d for pointer-free memory, not less than %d for pointerful memory", noscanDelta, scanDelta)
+	}
+}
+
+func TestReadMetricsScavengeRefaults(t *testing.T) {
+	// Return all free memory to the system, then allocate again,
+	// which must reuse scavenged (or fresh) memory.
+	scavengeSink = make([]byte, 64<<20)
+	scavengeSink = nil
+	debug.FreeOSMemory()
+	before := readMetric(t, "/memory/scavenge/refaults:faults").Uint64()
+
+	scavengeSink = make([]byte, 64<<20)
+	scavengeSink = nil
+	after := readMetric(t, "/memory/scavenge/refaults:faults").Uint64()
+	if after <= before {
+		t.Errorf("/memory/scavenge/refaults:faults did not increase after reusing scavenged memory: before %d, after %d", before, after)
+	}
+}
+
+func TestReadMetricsGCCycleDurations(t *testing.T) {
+	for i := 0; i < 5; i++ {
+		runtime.GC()
+	}
+	// Both metrics are updated with the world stopped, so a
+	// single Read sees them consistently.
+	s := []metrics.Sample{
+		{Name: "/gc/cycles/total:gc-cycles"},
+		{Name: "/gc/cycles/duration:seconds"},
+	}
+	metrics.Read(s)
+	cycles := s[0].Value.Uint64()
+	hist := s[1].Value.Float64Histogram()
+	var n uint64
+	for _, c := range hist.Counts {
+		n += c
+	}
+	if n != cycles {
+		t.Errorf("/gc/cycles/duration:seconds has %d samples, want %d (the number of completed cycles)", n, cycles)
+	}
+}
+
+func TestReadMetricsMarkWorkers(t *testing.T) {
+	procs := uint64(runtime.GOMAXPROCS(-1))
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		sink := make([][]*int, 1<<10)
+		for i := 0; ; i++ {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			sink[i%len(sink)] = make([]*int, 1<<10)
+		}
+	}()
+	for i := 0; i < 1000; i++ {
+		if n := readMetric(t, "/gc/mark/workers:goroutines").Uint64(); n > procs {
+			t.Errorf("/gc/mark/workers:goroutines = %d, want at most GOMAXPROCS (%d)", n, procs)
+		}
+	}
+	close(done)
+	wg.Wait()
+}
+
+var triggerSink []byte
+
+func TestReadMetricsTriggerRatio(t *testing.T) {
+	defer debug.SetGCPercent(debug.SetGCPercent(100))
+	// Use a live heap large enough that GOGC, rather than the
+	// minimum heap size, determines the heap goal.
+	triggerSink = make([]byte, 64<<20)
+	defer func() { triggerSink = nil }()
+	runtime.GC()
+	ratio := readMetric(t, "/gc/pacer/trigger-ratio:ratio").Float64()
+	if ratio <= 0 || ratio > 1 {
+		t.Errorf("/gc/pacer/trigger-ratio:ratio = %f with GOGC=100, want a value in (0, 1]", ratio)
+	}
+}
+
+var nextTriggerSink []byte
+
+func TestReadMetricsNextTrigger(t *testing.T) {
+	defer debug.SetGCPercent(debug.SetGCPercent(100))
+	triggerSink = make([]byte, 64<<20)
+	defer func() { triggerSink = nil }()
+	runtime.GC()
+
+	read := func() (trigger, goal, objects, cycles uint64) {
+		s := []metrics.Sample{
+			{Name: "/gc/heap/next-trigger:bytes"},
+			{Name: "/gc/heap/goal:bytes"},
+			{Name: "/memory/classes/heap/objects:bytes"},
+			{Name: "/gc/cycles/total:gc-cycles"},
+		}
+		metrics.Read(s)
+		return s[0].Value.Uint64(), s[1].Value.Uint64(), s[2].Value.Uint64(), s[3].Value.Uint64()
+	}
+	trigger, goal, objects, cycles := read()
+	if trigger <= objects || trigger > goal {
+		t.Fatalf("next trigger %d is not between the heap in use %d and the heap goal %d", trigger, objects, goal)
+	}
+
+	// Allocate part of the way to the trigger. That shouldn't start a GC,
+	// and the trigger should still be ahead of the heap.
+	nextTriggerSink = make([]byte, (trigger-objects)/4)
+	defer func() { nextTriggerSink = nil }()
+	trigger2, goal2, objects2, cycles2 := read()
+	if cycles2 != cycles {
+		t.Skip("a GC cycle ran while allocating toward the trigger")
+	}
+	if trigger2 <= objects2 || trigger2 > goal2 {
+		t.Errorf("after allocating %d bytes, next trigger %d is not between the heap in use %d and the heap goal %d", len(nextTriggerSink), trigger2, objects2, goal2)
+	}
+}
+
+var arenaSink []byte
+
+func TestReadMetricsHeapArenas(t *testing.T) {
+	before := readMetric(t, "/memory/heap/arenas:objects").Uint64()
+	if before < 1 {
+		t.Fatalf("/memory/heap/arenas:objects = %d, want at least 1", before)
+	}
+
+	// Growing the heap by at least a whole arena needs a new one.
+	skipUnlessHeapMustGrow(t)
+	arenaSink = make([]byte, heapGrowthSize)
+	arenaSink = nil
+	after := readMetric(t, "/memory/heap/arenas:objects").Uint64()
+	if after <= before {
+		t.Errorf("/memory/heap/arenas:objects did not grow after a large allocation: before %d, after %d", before, after)
+	}
+}
+
+var chunkSink []byte
+
+func TestReadMetricsHeapChunks(t *testing.T) {
+	const chunkBytes = 4 << 20
+	read := func() (chunks, reserved uint64) {
+		s := []metrics.Sample{
+			{Name: "/memory/heap/chunks:objects"},
+			{Name: "/memory/heap/arenas/reserved:bytes"},
+		}
+		metrics.Read(s)
+		return s[0].Value.Uint64(), s[1].Value.Uint64()
+	}
+	before, reserved := read()
+	if before < 1 {
+		t.Fatalf("/memory/heap/chunks:objects = %d, want at least 1", before)
+	}
+	if before*chunkBytes > reserved {
+		t.Errorf("%d chunks don't fit in the %d bytes reserved for heap arenas", before, reserved)
+	}
+
+	// Growing the heap by more than a chunk needs more of them.
+	skipUnlessHeapMustGrow(t)
+	chunkSink = make([]byte, heapGrowthSize)
+	chunkSink = nil
+	after, reserved := read()
+	if after <= before {
+		t.Errorf("/memory/heap/chunks:objects did not grow after a large allocation: before %d, after %d", before, after)
+	}
+	if after*chunkBytes > reserved {
+		t.Errorf("%d chunks don't fit in the %d bytes reserved for heap arenas", after, reserved)
+	}
+}
+
+var sweepSink []*[16]int
+
+func TestReadMetricsSweptSpans(t *testing.T) {
+	before := readMetric(t, "/gc/sweep/spans:spans").Uint64()
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 1<<12; j++ {
+			sweepSink = append(sweepSink, new([16]int))
+		}
+		sweepSink = nil
+		runtime.GC()
+	}
+	after := readMetric(t, "/gc/sweep/spans:spans").Uint64()
+	if after <= before {
+		t.Errorf("/gc/sweep/spans:spans did not increase over several GC cycles: before %d, after %d", before, after)
+	}
+}
+
+var meanSizeSink []*[64]byte
+
+func TestReadMetricsAllocMeanSize(t *testing.T) {
+	// Disable the GC so the only cycle boundaries are the ones below.
+	defer debug.SetGCPercent(debug.SetGCPercent(-1))
+
+	runtime.GC()
+	meanSizeSink = make([]*[64]byte, 0, 1<<16)
+	for i := 0; i < cap(meanSizeSink); i++ {
+		meanSizeSink = append(meanSizeSink, new([64]byte))
+	}
+	runtime.GC()
+	meanSizeSink = nil
+
+	// The mean includes the single large backing array for the sink
+	// and whatever else the runtime allocated during the cycle, so
+	// allow some slack.
+	const want = 64
+	mean := readMetric(t, "/gc/heap/allocs/mean-size:bytes").Float64()
+	if mean < want*0.9 || mean > want*1.2 {
+		t.Errorf("mean allocation size %f bytes is not close to %d bytes", mean, want)
+	}
+}
+
+var memLimitSink []byte
+
+func TestReadMetricsMemoryLimitExceeded(t *testing.T) {
+	before := readMetric(t, "/gc/gomemlimit/exceeded:events").Uint64()
+
+	// Keep a live heap well beyond a tiny limit across a GC cycle.
+	memLimitSink = make([]byte, 4<<20)
+	defer debug.SetMemoryLimit(debug.SetMemoryLimit(1 << 20))
+	runtime.GC()
+	runtime.KeepAlive(memLimitSink)
+	memLimitSink = nil
+
+	after := readMetric(t, "/gc/gomemlimit/exceeded:events").Uint64()
+	if after <= before {
+		t.Errorf("/gc/gomemlimit/exceeded:events did not increase with a live heap larger than the memory limit: before %d, after %d", before, after)
+	}
+}
+
+var pacerDelaySink []byte
+
+func TestReadMetricsPacerDelays(t *testing.T) {
+	// Keep a live heap around, then set a memory limit just above
+	// current memory use so the heap goal lands close to the live heap.
+	// The pacer then keeps the trigger from following its runway estimate
+	// all the way down.
+	live := make([]byte, 16<<20)
+	runtime.GC()
+	total := readMetric(t, "/memory/classes/total:bytes").Uint64()
+	defer debug.SetMemoryLimit(debug.SetMemoryLimit(int64(total + 4<<20)))
+
+	before := readMetric(t, "/gc/pacer/delays:events").Uint64()
+	after := before
+	for i := 0; i < 1<<14 && after == before; i++ {
+		pacerDelaySink = make([]byte, 64<<10)
+		if i%64 == 0 {
+			after = readMetric(t, "/gc/pacer/delays:events").Uint64()
+		}
+	}
+	pacerDelaySink = nil
+	runtime.KeepAlive(live)
+	if after == before {
+		t.Errorf("/gc/pacer/delays:events did not increase with the heap goal near the live heap: stayed at %d", before)
+	}
+}
+
+func TestReadMetricsUptime(t *testing.T) {
+	before := readMetric(t, "/process/uptime:seconds").Float64()
+	if before <= 0 {
+		t.Fatalf("uptime %f seconds is not positive", before)
+	}
+	const sleep = 100 * time.Millisecond
+	start := time.Now()
+	time.Sleep(sleep)
+	elapsed := time.Since(start).Seconds()
+	after := readMetric(t, "/process/uptime:seconds").Float64()
+
+	// The metric is sampled just outside the interval measured by
+	// the time package, so it may only come out slightly longer.
+	if diff := after - before; diff < sleep.Seconds() || diff > elapsed+0.05 {
+		t.Errorf("uptime advanced by %f seconds over a %f second sleep", diff, elapsed)
+	}
+}
+
+var profSink *[128]byte
+
+//go:noinline
+func profAllocSiteA() { profSink = new([128]byte) }
+
+//go:noinline
+func profAllocSiteB() { profSink = new([128]byte) }
+
+//go:noinline
+func profAllocSiteC() { profSink = new([128]byte) }
+
+func TestReadMetricsHeapProfileRecords(t *testing.T) {
+	// Sample every allocation so each call site gets a record.
+	defer func(rate int) { runtime.MemProfileRate = rate }(runtime.MemProfileRate)
+	runtime.MemProfileRate = 1
+
+	before := readMetric(t, "/profiling/heap/records:records").Uint64()
+	profAllocSiteA()
+	profAllocSiteB()
+	profAllocSiteC()
+	profSink = nil
+	after := readMetric(t, "/profiling/heap/records:records").Uint64()
+	// The call sites may already have records if the test ran before,
+	// so only check that the records exist and didn't go away.
+	if after == 0 || after < before {
+		t.Errorf("heap profile records went from %d to %d after allocating from 3 call sites", before, after)
+	}
+}
+
+func TestReadMetricsRuntimeLockContentions(t *testing.T) {
+	if runtime.GOOS == "js" {
+		t.Skip("runtime locks can't be contended without threads")
+	}
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(2))
+	// ContendRuntimeLock holds a runtime lock until it's contended,
+	// which would keep a GC from stopping the world.
+	defer debug.SetGCPercent(debug.SetGCPercent(-1))
+
+	before := readMetric(t, "/sync/runtime-locks/contentions:events").Uint64()
+	runtime.ContendRuntimeLock()
+	after := readMetric(t, "/sync/runtime-locks/contentions:events").Uint64()
+	if after <= before {
+		t.Errorf("/sync/runtime-locks/contentions:events did not advance after a contended lock: before %d, after %d", before, after)
+	}
+}
+
+func TestReadMetricsGoroutinesCreated(t *testing.T) {
+	const n = 100
+	liveBefore := readMetric(t, "/sched/goroutines:goroutines").Uint64()
+	createdBefore := readMetric(t, "/sched/goroutines/created:goroutines").Uint64()
+
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go wg.Done()
+	}
+	wg.Wait()
+
+	createdAfter := readMetric(t, "/sched/goroutines/created:goroutines").Uint64()
+	if createdAfter < createdBefore+n {
+		t.Errorf("created goroutines went from %d to %d after starting %d goroutines", createdBefore, createdAfter, n)
+	}
+
+	// The goroutines may not have finished exiting after Done.
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		live := readMetric(t, "/sched/goroutines:goroutines").Uint64()
+		if live <= liveBefore {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Errorf("live goroutines did not return to %d after %d exited, still %d", liveBefore, n, live)
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+type assistWaitNode struct {
+	next *assistWaitNode
+	_    [6]uintptr
+}
+
+var assistWaitSink *assistWaitNode
+
+func TestReadMetricsGCAssistWait(t *testing.T) {
+	// A long linked list can only be marked one node at a time, so
+	// while one worker walks it there is no work for assists to do
+	// and goroutines that owe assist credit have to wait for it. That
+	// needs more than one P, even if there's only one CPU.
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+	for i := 0; i < 1<<20; i++ {
+		assistWaitSink = &assistWaitNode{next: assistWaitSink}
+	}
+	defer func() { assistWaitSink = nil }()
+
+	before := readMetric(t, "/gc/assist/wait:seconds").Float64()
+	if !driveGCAssists(func() bool {
+		return readMetric(t, "/gc/assist/wait:seconds").Float64() > before
+	}) {
+		t.Error("/gc/assist/wait:seconds did not increase under heavy allocation")
+	}
+}
+
+func TestReadMetricsPoolDrained(t *testing.T) {
+	before := readMetric(t, "/sync/pool/drained:objects").Uint64()
+
+	const n = 100
+	var p sync.Pool
+	for i := 0; i < n; i++ {
+		p.Put(new(int))
+	}
+	// The first GC moves the pool's contents to its victim cache
+	// and the second drops them.
+	runtime.GC()
+	runtime.GC()
+	runtime.KeepAlive(&p)
+
+	after := readMetric(t, "/sync/pool/drained:objects").Uint64()
+	if after <= before {
+		t.Errorf("/sync/pool/drained:objects did not advance after dropping a full pool: before %d, after %d", before, after)
+	}
+}
+
+func TestReadMetricsStealing(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+
+	read := func() (attempts, successes uint64) {
+		s := []metrics.Sample{
+			{Name: "/sched/steal/attempts:events"},
+			{Name: "/sched/steal/successes:events"},
+		}
+		metrics.Read(s)
+		return s[0].Value.Uint64(), s[1].Value.Uint64()
+	}
+	attemptsBefore, successesBefore := read()
+
+	// Goroutines started by one goroutine all land on its P's run
+	// queue, leaving the other Ps to steal them.
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		var wg sync.WaitGroup
+		results := make([]int, 1000)
+		for i := range results {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				for j := 0; j < 1000; j++ {
+					results[i] += j
+				}
+			}(i)
+		}
+		wg.Wait()
+		attempts, successes := read()
+		if attempts > attemptsBefore && successes > successesBefore {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("steal attempts went from %d to %d and successes from %d to %d with uneven work", attemptsBefore, attempts, successesBefore, successes)
+		}
+	}
+}
+
+func TestReadMetricsGOGC(t *testing.T) {
+	orig := debug.SetGCPercent(50)
+	defer debug.SetGCPercent(orig)
+
+	if got := readMetric(t, "/gc/gogc:percent").Uint64(); got != 50 {
+		t.Errorf("/gc/gogc:percent is %d after SetGCPercent(50)", got)
+	}
+	debug.SetGCPercent(-1)
+	if got := int64(readMetric(t, "/gc/gogc:percent").Uint64()); got != -1 {
+		t.Errorf("/gc/gogc:percent is %d as an int64 with the GC disabled, want -1", got)
+	}
+}
+
+func TestReadMetricsGCPausePhases(t *testing.T) {
+	// Disable the GC so no cycle runs concurrently with the reads.
+	defer debug.SetGCPercent(debug.SetGCPercent(-1))
+
+	names := []string{
+		"/gc/pauses:seconds",
+		"/gc/pauses/sweep-termination:seconds",
+		"/gc/pauses/mark-termination:seconds",
+	}
+	read := func() []uint64 {
+		s := make([]metrics.Sample, len(names))
+		for i := range s {
+			s[i].Name = names[i]
+		}
+		metrics.Read(s)
+		totals := make([]uint64, len(s))
+		for i := range s {
+			for _, c := range s[i].Value.Float64Histogram().Counts {
+				totals[i] += c
+			}
+		}
+		return totals
+	}
+	before := read()
+	for i := 0; i < 3; i++ {
+		runtime.GC()
+	}
+	after := read()
+
+	for i := 1; i < len(names); i++ {
+		if after[i] < before[i]+3 {
+			t.Errorf("%s went from %d to %d pauses over 3 GC cycles", names[i], before[i], after[i])
+		}
+	}
+	if after[1]+after[2] != after[0] {
+		t.Errorf("sweep termination pauses (%d) and mark termination pauses (%d) don't add up to all GC pauses (%d)", after[1], after[2], after[0])
+	}
+}
+
+var markDurationSink []*[8]int
+
+func TestReadMetricsMarkDuration(t *testing.T) {
+	// Disable the GC so that only the cycles forced below run.
+	defer debug.SetGCPercent(debug.SetGCPercent(-1))
+
+	// Give the GC a large heap to mark.
+	markDurationSink = make([]*[8]int, 1<<20)
+	for i := range markDurationSink {
+		markDurationSink[i] = new([8]int)
+	}
+	defer func() { markDurationSink = nil }()
+
+	read := func() (marks, cycles uint64) {
+		s := []metrics.Sample{
+			{Name: "/gc/mark/duration:seconds"},
+			{Name: "/gc/cycles/duration:seconds"},
+		}
+		metrics.Read(s)
+		for _, c := range s[0].Value.Float64Histogram().Counts {
+			marks += c
+		}
+		for _, c := range s[1].Value.Float64Histogram().Counts {
+			cycles += c
+		}
+		return marks, cycles
+	}
+	marksBefore, _ := read()
+	const n = 3
+	for i := 0; i < n; i++ {
+		runtime.GC()
+	}
+	marksAfter, cyclesAfter := read()
+
+	if marks := marksAfter - marksBefore; marks != n {
+		t.Errorf("/gc/mark/duration:seconds gained %d samples over %d GC cycles", marks, n)
+	}
+	if marksAfter != cyclesAfter {
+		t.Errorf("/gc/mark/duration:seconds has %d samples, but /gc/cycles/duration:seconds has %d", marksAfter, cyclesAfter)
+	}
+}
+
+func TestReadMetricsLargeAllocs(t *testing.T) {
+	const n = 10
+	before := readMetric(t, "/gc/heap/allocs/large:objects").Uint64()
+	for i := 0; i < n; i++ {
+		largeAllocSink = make([]byte, 64<<10)
+	}
+	after := readMetric(t, "/gc/heap/allocs/large:objects").Uint64()
+	if after < before+n {
+		t.Errorf("large allocations went from %d to %d after %d large allocations", before, after, n)
+	}
+
+	for i := 0; i < 1000; i++ {
+		largeAllocSink = make([]byte, 1<<10)
+	}
+	largeAllocSink = nil
+	if got := readMetric(t, "/gc/heap/allocs/large:objects").Uint64(); got != after {
+		t.Errorf("large allocations went from %d to %d after only small allocations", after, got)
+	}
+}
+
+var scavAssistSink [][]byte
+
+func TestReadMetricsScavengeAssistRatio(t *testing.T) {
+	// Leave two large free, unreleased regions behind, kept apart by a
+	// live allocation between them.
+	scavAssistSink = [][]byte{make([]byte, 64<<20), make([]byte, 1<<20), make([]byte, 64<<20)}
+	scavAssistSink[0], scavAssistSink[2] = nil, nil
+	runtime.GC()
+
+	// Then set the memory limit to what's mapped now and make an
+	// allocation too large for either region. It has to be backed by
+	// newly mapped memory, so the allocating goroutine has to release
+	// free memory to stay within the limit.
+	s := []metrics.Sample{
+		{Name: "/memory/classes/total:bytes"},
+		{Name: "/memory/classes/heap/released:bytes"},
+	}
+	metrics.Read(s)
+	mapped := s[0].Value.Uint64() - s[1].Value.Uint64()
+	defer debug.SetMemoryLimit(debug.SetMemoryLimit(int64(mapped)))
+	scavAssistSink = append(scavAssistSink, make([]byte, 100<<20))
+	scavAssistSink = nil
+
+	ratio := readMetric(t, "/memory/scavenge/assist-ratio:ratio").Float64()
+	if ratio <= 0 || ratio > 1 || math.IsNaN(ratio) {
+		t.Errorf("scavenge assist ratio is %f under a binding memory limit, want a value in (0, 1]", ratio)
+	}
+}
+
+var scavCPUSink []byte
+
+func TestReadMetricsScavengeCPUTime(t *testing.T) {
+	before := readMetric(t, "/cpu/classes/scavenge/total:cpu-seconds").Float64()
+
+	// Leave a large free region behind, then set the memory limit below
+	// what's mapped so the scavenger has to release some of it.
+	scavCPUSink = make([]byte, 64<<20)
+	scavCPUSink = nil
+	runtime.GC()
+	s := []metrics.Sample{
+		{Name: "/memory/classes/total:bytes"},
+		{Name: "/memory/classes/heap/released:bytes"},
+	}
+	metrics.Read(s)
+	mapped := s[0].Value.Uint64() - s[1].Value.Uint64()
+	defer debug.SetMemoryLimit(debug.SetMemoryLimit(int64(mapped - 32<<20)))
+	runtime.GC()
+
+	// The background scavenger works at its own pace, so wait for it.
+	after := before
+	for deadline := time.Now().Add(5 * time.Second); after <= before && time.Now().Before(deadline); {
+		time.Sleep(10 * time.Millisecond)
+		after = readMetric(t, "/cpu/classes/scavenge/total:cpu-seconds").Float64()
+	}
+	if after <= before {
+		t.Errorf("scavenger CPU time did not increase under a binding memory limit: before %f, after %f", before, after)
+	}
+}
+
+func TestReadMetricsScavengeCPUUtilization(t *testing.T) {
+	check := func() {
+		t.Helper()
+		v := readMetric(t, "/memory/scavenge/cpu-utilization:ratio").Float64()
+		if math.IsNaN(v) || v < 0 || v > 1 {
+			t.Errorf("scavenger CPU utilization %f is outside [0, 1]", v)
+		}
+	}
+	check()
+
+	// Give the background scavenger something to do, as in
+	// TestReadMetricsScavengeCPUTime, and sample it while it works.
+	scavCPUSink = make([]byte, 64<<20)
+	scavCPUSink = nil
+	runtime.GC()
+	s := []metrics.Sample{
+		{Name: "/memory/classes/total:bytes"},
+		{Name: "/memory/classes/heap/released:bytes"},
+	}
+	metrics.Read(s)
+	mapped := s[0].Value.Uint64() - s[1].Value.Uint64()
+	defer debug.SetMemoryLimit(debug.SetMemoryLimit(int64(mapped - 32<<20)))
+	runtime.GC()
+
+	for i := 0; i < 50; i++ {
+		time.Sleep(10 * time.Millisecond)
+		check()
+	}
+}
+
+func TestReadMetricsIdleCPU(t *testing.T) {
+	const d = 100 * time.Millisecond
+	idle := func(f func()) float64 {
+		before := readMetric(t, "/cpu/classes/idle:cpu-seconds").Float64()
+		f()
+		return readMetric(t, "/cpu/classes/idle:cpu-seconds").Float64() - before
+	}
+
+	// Keep every P busy for d, then leave them all idle for d.
+	busy := idle(func() {
+		var wg sync.WaitGroup
+		for i := 0; i < runtime.GOMAXPROCS(0); i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for start := time.Now(); time.Since(start) < d; {
+				}
+			}()
+		}
+		wg.Wait()
+	})
+	sleeping := idle(func() {
+		time.Sleep(d)
+	})
+	if sleeping <= busy {
+		t.Errorf("idle CPU time grew by %fs while sleeping, no more than %fs while busy", sleeping, busy)
+	}
+}
+
+func TestReadMetricsUserCPU(t *testing.T) {
+	runtime.GC()
+	before := readMetric(t, "/cpu/classes/user:cpu-seconds").Float64()
+
+	// Spin on one goroutine, which must count as user time.
+	const d = 200 * time.Millisecond
+	for start := time.Now(); time.Since(start) < d; {
+	}
+
+	after := readMetric(t, "/cpu/classes/user:cpu-seconds").Float64()
+	if after-before < d.Seconds()/2 {
+		t.Errorf("user CPU time grew by %fs while spinning for %s", after-before, d)
+	}
+}
+
+func TestReadMetricsTotalCPU(t *testing.T) {
+	const d = 100 * time.Millisecond
+	procs := float64(runtime.GOMAXPROCS(0))
+	start := time.Now()
+	before := readMetric(t, "/cpu/classes/total:cpu-seconds").Float64()
+	time.Sleep(d)
+	after := readMetric(t, "/cpu/classes/total:cpu-seconds").Float64()
+	elapsed := time.Since(start)
+
+	// The reads happen at least d apart, and within elapsed.
+	delta := after - before
+	if min, max := procs*d.Seconds(), procs*elapsed.Seconds(); delta < min*0.99 || delta > max*1.01 {
+		t.Errorf("total CPU time grew by %fs over %s with GOMAXPROCS=%d, want between %fs and %fs",
+			delta, elapsed, int(procs), min, max)
+	}
+}
+
+func TestReadMetricsCPUClassesSum(t *testing.T) {
+	var samples []metrics.Sample
+	for _, d := range metrics.All() {
+		if strings.HasPrefix(d.Name, "/cpu/classes/") {
+			samples = append(samples, metrics.Sample{Name: d.Name})
+		}
+	}
+	for i := 0; i < 10; i++ {
+		// Read with a GC in progress some of the time.
+		if i%2 == 0 {
+			go runtime.GC()
+		}
+		metrics.Read(samples)
+		var total, sum float64
+		for _, s := range samples {
+			if s.Name == "/cpu/classes/total:cpu-seconds" {
+				total = s.Value.Float64()
+			} else {
+				sum += s.Value.Float64()
+			}
+		}
+		if math.Abs(total-sum) > total*1e-9 {
+			t.Errorf("/cpu/classes metrics sum to %fs, want %fs", sum, total)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestReadMetricsPreemptions(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(2))
+	before := readMetric(t, "/sched/preemptions:events").Uint64()
+
+	// A loop without function calls can only be preempted
+	// asynchronously, which the GC's stop-the-world forces.
+	var stop atomic.Bool
+	done := make(chan struct{})
+	go func() {
+		for !stop.Load() {
+		}
+		close(done)
+	}()
+	for i := 0; i < 5; i++ {
+		runtime.GC()
+	}
+	stop.Store(true)
+	<-done
+
+	after := readMetric(t, "/sched/preemptions:events").Uint64()
+	if after <= before {
+		t.Errorf("/sched/preemptions:events did not advance: before %d, after %d", before, after)
+	}
+}
+
+var pageCacheSink [4][][]byte
+
+func TestReadMetricsPageCache(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+	read := func() (hits, misses uint64) {
+		s := []metrics.Sample{
+			{Name: "/memory/page-cache/hits:events"},
+			{Name: "/memory/page-cache/misses:events"},
+		}
+		metrics.Read(s)
+		return s[0].Value.Uint64(), s[1].Value.Uint64()
+	}
+	hitsBefore, missesBefore := read()
+
+	// Large allocations of a few pages each are served from the
+	// page cache, which has to be refilled every few allocations.
+	var wg sync.WaitGroup
+	for i := range pageCacheSink {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 256; j++ {
+				pageCacheSink[i] = append(pageCacheSink[i], make([]byte, 40<<10))
+			}
+		}(i)
+	}
+	wg.Wait()
+	pageCacheSink = [4][][]byte{}
+
+	hits, misses := read()
+	if hits <= hitsBefore {
+		t.Errorf("/memory/page-cache/hits:events did not advance: before %d, after %d", hitsBefore, hits)
+	}
+	if misses <= missesBefore {
+		t.Errorf("/memory/page-cache/misses:events did not advance: before %d, after %d", missesBefore, misses)
+	}
+}
+
+func TestReadMetricsChannelBlockTime(t *testing.T) {
+	before := readMetric(t, "/sync/channels/block-time:seconds").Float64()
+
+	// The consumer keeps blocking on the slow producer.
+	const (
+		n     = 10
+		delay = 10 * time.Millisecond
+	)
+	c := make(chan int)
+	go func() {
+		for i := 0; i < n; i++ {
+			time.Sleep(delay)
+			c <- i
+		}
+		close(c)
+	}()
+	for range c {
+	}
+
+	after := readMetric(t, "/sync/channels/block-time:seconds").Float64()
+	if want := (n * delay).Seconds() / 2; after-before < want {
+		t.Errorf("channel block time grew by %fs, want at least %fs", after-before, want)
+	}
+}
+
+var mcentralSink [8][]*[64]byte
+
+func TestReadMetricsMCentralContentions(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(8))
+	before := readMetric(t, "/sync/mcentral/contentions:events").Uint64()
+
+	// Allocate objects of one size class from many goroutines at once,
+	// so they all keep going back to the same central span lists.
+	var wg sync.WaitGroup
+	for i := range mcentralSink {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 1<<14; j++ {
+				mcentralSink[i] = append(mcentralSink[i], new([64]byte))
+			}
+		}(i)
+	}
+	wg.Wait()
+	mcentralSink = [8][]*[64]byte{}
+
+	// Whether the goroutines actually collide depends on how many
+	// CPUs there are and on scheduling, so only check that the count
+	// is consistent.
+	after := readMetric(t, "/sync/mcentral/contentions:events").Uint64()
+	if after < before {
+		t.Errorf("/sync/mcentral/contentions:events decreased from %d to %d", before, after)
+	}
+	t.Logf("%d contentions", after-before)
+}
+
+// freelistSink is an array, not a slice, so that it isn't itself
+// freed to the page heap.
+var freelistSink [1 << 14]*[64]byte
+
+func TestReadMetricsFreesToFreelist(t *testing.T) {
+	read := func() (freelist, released uint64) {
+		s := []metrics.Sample{
+			{Name: "/gc/heap/frees-to-freelist:bytes"},
+			{Name: "/gc/scavenge/released:bytes"},
+		}
+		metrics.Read(s)
+		return s[0].Value.Uint64(), s[1].Value.Uint64()
+	}
+
+	// The background scavenger may still be releasing memory freed by
+	// other tests, so wait for it to finish, and retry if it releases
+	// anything in the middle anyway.
+	const n = len(freelistSink)
+	for try := 0; ; try++ {
+		runtime.GC()
+		_, releasedBefore := read()
+		for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); {
+			time.Sleep(50 * time.Millisecond)
+			_, released := read()
+			if released == releasedBefore {
+				break
+			}
+			releasedBefore = released
+		}
+		freelistBefore, releasedBefore := read()
+
+		// Free most of many small objects, but keep some alive, so
+		// that their spans stay in use and the freed memory can only
+		// go back to the spans' free lists, not to the system.
+		for i := range freelistSink {
+			freelistSink[i] = new([64]byte)
+		}
+		for i := range freelistSink {
+			if i%32 != 0 {
+				freelistSink[i] = nil
+			}
+		}
+		runtime.GC()
+		runtime.GC()
+		freelistSink = [n]*[64]byte{}
+
+		freelist, released := read()
+		if released != releasedBefore && try < 5 {
+			continue
+		}
+		if want := uint64(n-n/32) * 64; freelist-freelistBefore < want {
+			t.Errorf("/gc/heap/frees-to-freelist:bytes advanced by %d, want at least %d", freelist-freelistBefore, want)
+		}
+		// Other memory freed by the GCs, such as stack spans, may still
+		// be released, but that's far less than what was freed here.
+		if released-releasedBefore >= (freelist-freelistBefore)/4 {
+			t.Errorf("/gc/scavenge/released:bytes advanced by %d, with %d bytes freed to free lists",
+				released-releasedBefore, freelist-freelistBefore)
+		}
+		break
+	}
+}
+
+var tinySink []*[4]byte
+
+func TestReadMetricsTinyObjects(t *testing.T) {
+	read := func() (objects, allocs uint64) {
+		s := []metrics.Sample{
+			{Name: "/gc/heap/tiny/objects:objects"},
+			{Name: "/gc/heap/tiny/allocs:objects"},
+		}
+		metrics.Read(s)
+		return s[0].Value.Uint64(), s[1].Value.Uint64()
+	}
+	objectsBefore, _ := read()
+
+	// Perform many tiny allocations, filling many tiny blocks, and
+	// through them many spans, so that the counts get flushed.
+	const n = 1 << 16
+	tinySink = make([]*[4]byte, n)
+	for i := range tinySink {
+		tinySink[i] = new([4]byte)
+	}
+	tinySink = nil
+
+	objects, allocs := read()
+	// The P's cache may hold on to the counts for the last span.
+	if objects-objectsBefore < n/2 {
+		t.Errorf("tiny objects went from %d to %d after %d tiny allocations", objectsBefore, objects, n)
+	}
+	if objects <= allocs {
+		t.Errorf("tiny objects %d not more than tiny allocs packed into existing blocks %d", objects, allocs)
+	}
+}
+
+var allocPathSink []*[48]byte
+
+func TestReadMetricsAllocPaths(t *testing.T) {
+	read := func() (fast, slow uint64) {
+		s := []metrics.Sample{
+			{Name: "/gc/heap/allocs/fast-path:objects"},
+			{Name: "/gc/heap/allocs/slow-path:objects"},
+		}
+		metrics.Read(s)
+		return s[0].Value.Uint64(), s[1].Value.Uint64()
+	}
+	fastBefore, slowBefore := read()
+
+	// Allocate enough to fill many spans, so the P's cache has to be
+	// refilled, which also flushes the counts.
+	allocPathSink = make([]*[48]byte, 0, 1<<16)
+	for i := 0; i < cap(allocPathSink); i++ {
+		allocPathSink = append(allocPathSink, new([48]byte))
+	}
+	allocPathSink = nil
+
+	fastAfter, slowAfter := read()
+	if slowAfter <= slowBefore {
+		t.Errorf("slow-path allocations did not advance after filling many spans: before %d, after %d", slowBefore, slowAfter)
+	}
+	if fastAfter <= fastBefore {
+		t.Errorf("fast-path allocations did not advance after filling many spans: before %d, after %d", fastBefore, fastAfter)
+	}
+}
+
+var (
+	sizeClassSink16  []*[16]byte
+	sizeClassSink512 []*[512]byte
+	sizeClassSink4K  []*[4096]byte
+)
+
+func TestReadMetricsActiveSizeClasses(t *testing.T) {
+	// Keep many objects of 3 sizes in distinct size classes alive, so
+	// that the spans holding them are flushed from the P's cache.
+	for i := 0; i < 1<<10; i++ {
+		sizeClassSink16 = append(sizeClassSink16, new([16]byte))
+		sizeClassSink512 = append(sizeClassSink512, new([512]byte))
+		sizeClassSink4K = append(sizeClassSink4K, new([4096]byte))
+	}
+	active := readMetric(t, "/gc/heap/size-classes/active:classes").Uint64()
+	runtime.KeepAlive(sizeClassSink16)
+	runtime.KeepAlive(sizeClassSink512)
+	runtime.KeepAlive(sizeClassSink4K)
+	sizeClassSink16, sizeClassSink512, sizeClassSink4K = nil, nil, nil
+
+	if active < 3 {
+		t.Errorf("only %d size classes are active with live objects in at least 3", active)
+	}
+	// There are only so many size classes.
+	if n := len(readMetric(t, "/gc/heap/allocs-by-size:bytes").Float64Histogram().Counts); active > uint64(n) {
+		t.Errorf("%d size classes are active, but there are only %d", active, n)
+	}
+}
+
+var unaccountedSink [64 << 10]byte
+
+func TestReadMetricsUnaccounted(t *testing.T) {
+	unaccountedSink[0] = 1 // Make sure the BSS isn't empty.
+
+	s := []metrics.Sample{
+		{Name: "/memory/classes/total:bytes"},
+		{Name: "/memory/unaccounted:bytes"},
+	}
+	metrics.Read(s)
+	total, unaccounted := s[0].Value.Uint64(), s[1].Value.Uint64()
+
+	// The value is unsigned, so it can only be negative if it
+	// wrapped around, which would make the sum overflow.
+	if sum := total + unaccounted; sum < total {
+		t.Errorf("total %d + unaccounted %d overflows", total, unaccounted)
+	}
+	if unaccounted < uint64(len(unaccountedSink)) {
+		t.Errorf("unaccounted memory %d bytes smaller than a %d byte global", unaccounted, len(unaccountedSink))
+	}
+}
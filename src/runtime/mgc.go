@@ -661,6 +661,11 @@ func gcStart(trigger gcTrigger) {
 		traceGCSTWStart(1)
 	}
 	systemstack(stopTheWorldWithSema)
+	// Snapshot CPU time accounting for metrics, which report it as of
+	// the start of the cycle until the cycle's GC CPU time is known.
+	lock(&sched.lock)
+	memstats.gcStartCPUStats = readCPUStats(nanotime())
+	unlock(&sched.lock)
 	// Finish sweep before we start concurrent scan.
 	systemstack(func() {
 		finishsweep_m()
@@ -729,6 +734,7 @@ func gcStart(trigger gcTrigger) {
 		work.pauseNS += now - work.pauseStart
 		work.tMark = now
 		memstats.gcPauseDist.record(now - work.pauseStart)
+		memstats.gcSweepTermPauseDist.record(now - work.pauseStart)
 
 		// Release the CPU limiter.
 		gcCPULimiter.finishGCTransition(now)
@@ -747,6 +753,13 @@ func gcStart(trigger gcTrigger) {
 		Gosched()
 	}
 
+	// Let sync.Pool count the objects clearpools moved to victim
+	// caches, now that doing so doesn't extend the pause. Holding
+	// startSema keeps the next clearpools from running first.
+	if poolcount != nil {
+		poolcount()
+	}
+
 	semrelease(&work.startSema)
 }
 
@@ -880,6 +893,7 @@ top:
 			now := startTheWorldWithSema(true)
 			work.pauseNS += now - work.pauseStart
 			memstats.gcPauseDist.record(now - work.pauseStart)
+			memstats.gcMarkTermPauseDist.record(now - work.pauseStart)
 		})
 		semrelease(&worldsema)
 		goto top
@@ -987,6 +1001,9 @@ func gcMarkTermination() {
 	// Record heapInUse for scavenger.
 	memstats.lastHeapInUse = gcController.heapInUse.load()
 
+	// Record the mean size of objects allocated this cycle.
+	memstats.recordCycleAllocMean()
+
 	// Update GC trigger and pacing, as well as downstream consumers
 	// of this pacing information, for the next cycle.
 	systemstack(gcControllerCommit)
@@ -998,6 +1015,9 @@ func gcMarkTermination() {
 	work.pauseNS += now - work.pauseStart
 	work.tEnd = now
 	memstats.gcPauseDist.record(now - work.pauseStart)
+	memstats.gcMarkTermPauseDist.record(now - work.pauseStart)
+	memstats.gcCycleDist.record(now - work.tSweepTerm)
+	memstats.gcMarkDist.record(work.tMarkTerm - work.tMark)
 	atomic.Store64(&memstats.last_gc_unix, uint64(unixNow)) // must be Unix time to make sense to user
 	atomic.Store64(&memstats.last_gc_nanotime, uint64(now)) // monotonic time for us
 	memstats.pause_ns[memstats.numgc%uint32(len(memstats.pause_ns))] = uint64(work.pauseNS)
@@ -1012,6 +1032,11 @@ func gcMarkTermination() {
 	markTermCpu := int64(work.stwprocs) * (work.tEnd - work.tMarkTerm)
 	cycleCpu := sweepTermCpu + markCpu + markTermCpu
 	work.totaltime += cycleCpu
+	memstats.gcAssistTime.Add(gcController.assistTime.Load())
+	memstats.gcDedicatedMarkTime.Add(gcController.dedicatedMarkTime + gcController.fractionalMarkTime)
+	memstats.gcIdleMarkTime.Add(gcController.idleMarkTime)
+	memstats.gcPauseTime.Add(sweepTermCpu + markTermCpu)
+	memstats.gcScanWork.Add(gcController.heapScanWork.Load() + gcController.stackScanWork.Load() + gcController.globalsScanWork.Load())
 
 	// Compute overall GC CPU utilization.
 	totalCpu := sched.totaltime + (now-sched.procresizetime)*int64(gomaxprocs)
@@ -1555,12 +1580,18 @@ func gcResetMarkState() {
 
 // Hooks for other packages
 
-var poolcleanup func()
+var poolcleanup func() uint64
+var poolcount func()
+
+// poolDrained is the number of objects sync.Pool cleanup has
+// dropped from victim caches.
+var poolDrained atomic.Uint64
 var boringCaches []unsafe.Pointer // for crypto/internal/boring
 
 //go:linkname sync_runtime_registerPoolCleanup sync.runtime_registerPoolCleanup
-func sync_runtime_registerPoolCleanup(f func()) {
-	poolcleanup = f
+func sync_runtime_registerPoolCleanup(cleanup func() uint64, count func()) {
+	poolcleanup = cleanup
+	poolcount = count
 }
 
 //go:linkname boring_registerCache crypto/internal/boring.registerCache
@@ -1571,7 +1602,7 @@ func boring_registerCache(p unsafe.Pointer) {
 func clearpools() {
 	// clear sync.Pools
 	if poolcleanup != nil {
-		poolcleanup()
+		poolDrained.Add(int64(poolcleanup()))
 	}
 
 	// clear boringcrypto caches
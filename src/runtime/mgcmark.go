@@ -641,7 +641,9 @@ func gcParkAssist() bool {
 		return false
 	}
 	// Park.
+	start := nanotime()
 	goparkunlock(&work.assistQueue.lock, waitReasonGCAssistWait, traceEvGoBlockGC, 2)
+	memstats.gcAssistWaitTime.Add(nanotime() - start)
 	return true
 }
 
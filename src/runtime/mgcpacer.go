@@ -111,6 +111,15 @@ type gcControllerState struct {
 	// should never be negative.
 	memoryLimit atomic.Int64
 
+	// memoryLimitExceeded is the number of GC cycles that ended with the
+	// live heap alone larger than memoryLimit. Updated in resetLive.
+	memoryLimitExceeded atomic.Uint64
+
+	// delays is the number of heap-triggered GC cycles that started later
+	// than the runway estimate called for, because the trigger was raised
+	// to one of its lower bounds. Updated in startCycle.
+	delays atomic.Uint64
+
 	// heapMinimum is the minimum heap size at which to trigger GC.
 	// For small heaps, this overrides the usual GOGC*live set rule.
 	//
@@ -386,6 +395,15 @@ type gcControllerState struct {
 	totalFree    atomic.Uint64 // total bytes freed
 	mappedReady  atomic.Uint64 // total virtual memory in the Ready state (see mem.go).
 
+	// allocRate is the smoothed estimate of the application's heap
+	// allocation rate in bytes per second, stored as float64 bits. It is
+	// updated in endCycle from the bytes allocated since the end of the
+	// previous cycle, which lastEndCycleTime and lastEndCycleTotalAlloc
+	// record. The latter two are only accessed with the world stopped.
+	allocRate              atomic.Uint64
+	lastEndCycleTime       int64
+	lastEndCycleTotalAlloc uint64
+
 	// test indicates that this is a test-only copy of gcControllerState.
 	test bool
 
@@ -441,6 +459,15 @@ func (c *gcControllerState) startCycle(markStartTime int64, procs int, trigger g
 	c.markStartTime = markStartTime
 	c.triggered = c.heapLive
 
+	// Check whether the trigger that started this cycle was pushed past
+	// the point the runway estimate called for. See trigger.
+	if trigger.kind == gcTriggerHeap {
+		t, goal := c.trigger()
+		if runway := c.runway.Load(); runway >= goal || goal-runway < t {
+			c.delays.Add(1)
+		}
+	}
+
 	// Compute the background mark utilization goal. In general,
 	// this may not come out exactly. We round the number of
 	// dedicated workers so that the utilization is closest to
@@ -647,6 +674,8 @@ func (c *gcControllerState) endCycle(now int64, procs int, userForced bool) {
 	// We'll be updating the heap goal soon.
 	gcController.lastHeapGoal = c.heapGoal()
 
+	c.updateAllocRate(now)
+
 	// Compute the duration of time for which assists were turned on.
 	assistDuration := now - c.markStartTime
 
@@ -745,6 +774,28 @@ func (c *gcControllerState) endCycle(now int64, procs int, userForced bool) {
 	}
 }
 
+// updateAllocRate folds the allocation rate observed since the end of
+// the previous GC cycle, or since the program started if there was none,
+// into the smoothed allocation rate estimate.
+func (c *gcControllerState) updateAllocRate(now int64) {
+	total := c.totalAlloc.Load()
+	start := c.lastEndCycleTime
+	if start == 0 {
+		start = runtimeInitTime
+	}
+	if now > start {
+		rate := float64(total-c.lastEndCycleTotalAlloc) / (float64(now-start) / 1e9)
+		if last := float64frombits(c.allocRate.Load()); last != 0 {
+			// Give the history and the latest cycle equal weight,
+			// so one unusual cycle doesn't dominate the estimate.
+			rate = (last + rate) / 2
+		}
+		c.allocRate.Store(float64bits(rate))
+	}
+	c.lastEndCycleTime = now
+	c.lastEndCycleTotalAlloc = total
+}
+
 // enlistWorker encourages another dedicated mark worker to start on
 // another P if there are spare worker slots. It is used by putfull
 // when more work is made available.
@@ -886,6 +937,12 @@ func (c *gcControllerState) resetLive(bytesMarked uint64) {
 	c.lastStackScan = uint64(c.stackScanWork.Load())
 	c.triggered = ^uint64(0) // Reset triggered.
 
+	// If the live heap by itself doesn't fit within the memory limit,
+	// no amount of GC work can bring total memory use under it.
+	if go119MemoryLimitSupport && bytesMarked > uint64(c.memoryLimit.Load()) {
+		c.memoryLimitExceeded.Add(1)
+	}
+
 	// heapLive was updated, so emit a trace event.
 	if trace.enabled {
 		traceHeapAlloc()
@@ -1210,6 +1267,20 @@ func (c *gcControllerState) trigger() (uint64, uint64) {
 	return trigger, goal
 }
 
+// triggerRatio returns the current trigger expressed as heap growth
+// relative to heapMarked, or zero if nothing has been marked yet.
+func (c *gcControllerState) triggerRatio() float64 {
+	marked := c.heapMarked
+	if marked == 0 {
+		return 0
+	}
+	trigger, _ := c.trigger()
+	if trigger <= marked {
+		return 0
+	}
+	return float64(trigger-marked) / float64(marked)
+}
+
 // commit recomputes all pacing parameters needed to derive the
 // trigger and the heap goal. Namely, the gcPercent-based heap goal,
 // and the amount of runway we want to give the GC this cycle.
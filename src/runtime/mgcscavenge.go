@@ -280,6 +280,12 @@ type scavengerState struct {
 	// the controller was reset.
 	printControllerReset bool
 
+	// cpuUtilization is the scavenger's CPU utilization over its most
+	// recent work and sleep period, as a fraction of its CPU cap
+	// (scavengePercent of GOMAXPROCS). It is clamped to [0, 1], and set
+	// to zero when the scavenger parks with no more work to do.
+	cpuUtilization float64
+
 	// sleepStub is a stub used for testing to avoid actually having
 	// the scavenger sleep.
 	//
@@ -386,6 +392,7 @@ func (s *scavengerState) park() {
 		throw("tried to park scavenger from another goroutine")
 	}
 	s.parked = true
+	s.cpuUtilization = 0
 	goparkunlock(&s.lock, waitReasonGCScavengeWait, traceEvGoBlock, 2)
 }
 
@@ -483,6 +490,27 @@ func (s *scavengerState) sleep(worked float64) {
 		slept = s.sleepStub(sleepTime)
 	}
 
+	// idealFraction is the ideal % of overall application CPU time that we
+	// spend scavenging.
+	idealFraction := float64(scavengePercent) / 100.0
+
+	// Calculate the CPU time spent.
+	//
+	// This may be slightly inaccurate with respect to GOMAXPROCS, but we're
+	// recomputing this often enough relative to GOMAXPROCS changes in general
+	// (it only changes when the world is stopped, and not during a GC) that
+	// that small inaccuracy is in the noise.
+	cpuFraction := worked / ((float64(slept) + worked) * float64(s.gomaxprocs()))
+
+	// Publish how close we came to the CPU cap for metrics.
+	utilization := cpuFraction / idealFraction
+	if utilization > 1 {
+		utilization = 1
+	}
+	lock(&s.lock)
+	s.cpuUtilization = utilization
+	unlock(&s.lock)
+
 	// Stop here if we're cooling down from the controller.
 	if s.controllerCooldown > 0 {
 		// worked and slept aren't exact measures of time, but it's OK to be a bit
@@ -496,18 +524,6 @@ func (s *scavengerState) sleep(worked float64) {
 		return
 	}
 
-	// idealFraction is the ideal % of overall application CPU time that we
-	// spend scavenging.
-	idealFraction := float64(scavengePercent) / 100.0
-
-	// Calculate the CPU time spent.
-	//
-	// This may be slightly inaccurate with respect to GOMAXPROCS, but we're
-	// recomputing this often enough relative to GOMAXPROCS changes in general
-	// (it only changes when the world is stopped, and not during a GC) that
-	// that small inaccuracy is in the noise.
-	cpuFraction := worked / ((float64(slept) + worked) * float64(s.gomaxprocs()))
-
 	// Update the critSleepRatio, adjusting until we reach our ideal fraction.
 	var ok bool
 	s.sleepRatio, ok = s.sleepController.next(cpuFraction, idealFraction, float64(slept)+worked)
@@ -623,6 +639,8 @@ func bgscavenge(c chan int) {
 			continue
 		}
 		atomic.Xadduintptr(&mheap_.pages.scav.released, released)
+		mheap_.pages.scav.bgReleased.Add(int64(released))
+		mheap_.pages.scav.bgCPUTime.Add(int64(workTime))
 		scavenger.sleep(workTime)
 	}
 }
@@ -746,6 +764,7 @@ func (p *pageAlloc) scavengeOne(ci chunkIdx, searchIdx uint, max uintptr) uintpt
 				stats := memstats.heapStats.acquire()
 				atomic.Xaddint64(&stats.committed, -nbytes)
 				atomic.Xaddint64(&stats.released, nbytes)
+				atomic.Xadd64(&stats.scavenged, nbytes)
 				memstats.heapStats.release()
 			}
 
@@ -33,6 +33,25 @@ var sweep sweepdata
 
 // State of background sweep.
 type sweepdata struct {
+	// The following 64-bit fields are accessed atomically and kept
+	// at the top of the struct to ensure alignment on 32-bit systems.
+
+	// freed is the number of objects freed so far in the current
+	// sweep cycle. lastCycleFreed is the value of freed at the point
+	// the previous sweep cycle completed.
+	freed          atomic.Uint64
+	lastCycleFreed atomic.Uint64
+
+	// finRetained is the number of otherwise unreachable objects kept
+	// alive so far in the current sweep cycle because they have
+	// finalizers. lastCycleFinRetained is the value of finRetained at
+	// the point the previous sweep cycle completed.
+	finRetained          atomic.Uint64
+	lastCycleFinRetained atomic.Uint64
+
+	// spansSwept is the total number of spans swept.
+	spansSwept atomic.Uint64
+
 	lock    mutex
 	g       *g
 	parked  bool
@@ -173,9 +192,12 @@ func (a *activeSweep) end(sl sweepLocker) {
 			throw("mismatched begin/end of activeSweep")
 		}
 		if a.state.CompareAndSwap(state, state-1) {
-			if state != sweepDrainedMask {
+			if state-1 != sweepDrainedMask {
 				return
 			}
+			// This was the last sweeper, so the sweep cycle is done.
+			sweep.lastCycleFreed.Store(sweep.freed.Swap(0))
+			sweep.lastCycleFinRetained.Store(sweep.finRetained.Swap(0))
 			if debug.gcpacertrace > 0 {
 				print("pacer: sweep done at heap size ", gcController.heapLive>>20, "MB; allocated ", (gcController.heapLive-mheap_.sweepHeapLiveBasis)>>20, "MB during sweep; swept ", mheap_.pagesSwept.Load(), " pages at ", mheap_.sweepPagesPerByte, " pages/byte\n")
 			}
@@ -493,6 +515,7 @@ func (sl *sweepLocked) sweep(preserve bool) bool {
 	}
 
 	mheap_.pagesSwept.Add(int64(s.npages))
+	sweep.spansSwept.Add(1)
 
 	spc := s.spanclass
 	size := s.elemsize
@@ -533,6 +556,9 @@ func (sl *sweepLocked) sweep(preserve bool) bool {
 					break
 				}
 			}
+			if hasFin {
+				sweep.finRetained.Add(1)
+			}
 			// Pass 2: queue all finalizers _or_ handle profile record.
 			for siter.valid() && uintptr(siter.s.offset) < endOffset {
 				// Find the exact byte for which the special was setup
@@ -670,6 +696,7 @@ func (sl *sweepLocked) sweep(preserve bool) bool {
 			stats := memstats.heapStats.acquire()
 			atomic.Xadd64(&stats.smallFreeCount[spc.sizeclass()], int64(nfreed))
 			memstats.heapStats.release()
+			sweep.freed.Add(int64(nfreed))
 
 			// Count the frees in the inconsistent, internal stats.
 			gcController.totalFree.Add(int64(nfreed) * int64(s.elemsize))
@@ -723,6 +750,7 @@ func (sl *sweepLocked) sweep(preserve bool) bool {
 			atomic.Xadd64(&stats.largeFreeCount, 1)
 			atomic.Xadd64(&stats.largeFree, int64(size))
 			memstats.heapStats.release()
+			sweep.freed.Add(1)
 
 			// Count the free in the inconsistent, internal stats.
 			gcController.totalFree.Add(int64(size))
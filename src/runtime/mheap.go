@@ -1132,8 +1132,11 @@ func (h *mheap) allocSpan(npages uintptr, typ spanAllocType, spanclass spanClass
 	if !needPhysPageAlign && pp != nil && npages < pageCachePages/4 {
 		c := &pp.pcache
 
-		// If the cache is empty, refill it.
+		// If the cache is empty, refill it. That takes the heap
+		// lock, so it counts as a miss.
+		hit := true
 		if c.empty() {
+			hit = false
 			lock(&h.lock)
 			*c = h.pages.allocToCache()
 			unlock(&h.lock)
@@ -1141,6 +1144,14 @@ func (h *mheap) allocSpan(npages uintptr, typ spanAllocType, spanclass spanClass
 
 		// Try to allocate from the cache.
 		base, scav = c.alloc(npages)
+		if base == 0 {
+			hit = false
+		}
+		if hit {
+			pageCacheHits.Add(1)
+		} else {
+			pageCacheMisses.Add(1)
+		}
 		if base != 0 {
 			s = h.tryAllocMSpan()
 			if s != nil {
@@ -1299,8 +1310,10 @@ HaveSpan:
 		// Measure how long we spent scavenging and add that measurement to the assist
 		// time so we can track it for the GC CPU limiter.
 		start := nanotime()
-		h.pages.scavenge(bytesToScavenge)
+		released := h.pages.scavenge(bytesToScavenge)
 		now := nanotime()
+		h.pages.scav.assistReleased.Add(int64(released))
+		h.pages.scav.assistCPUTime.Add(now - start)
 		assistTime := h.pages.scav.assistTime.Add(now - start)
 		gcCPULimiter.update(gcController.assistTime.Load()+assistTime, now)
 	}
@@ -1321,6 +1334,7 @@ HaveSpan:
 	stats := memstats.heapStats.acquire()
 	atomic.Xaddint64(&stats.committed, int64(scav))
 	atomic.Xaddint64(&stats.released, -int64(scav))
+	atomic.Xadd64(&stats.recommitted, int64(scav))
 	switch typ {
 	case spanAllocHeap:
 		atomic.Xaddint64(&stats.inHeap, int64(nbytes))
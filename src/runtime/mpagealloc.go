@@ -286,6 +286,18 @@ type pageAlloc struct {
 		//
 		// This is reset once a GC cycle ends.
 		assistTime atomic.Int64
+
+		// assistReleased and bgReleased are the total bytes released
+		// by allocation assists and by the background scavenger
+		// respectively.
+		assistReleased atomic.Uint64
+		bgReleased     atomic.Uint64
+
+		// assistCPUTime and bgCPUTime are the total nanoseconds spent
+		// scavenging by allocation assists and by the background
+		// scavenger respectively.
+		assistCPUTime atomic.Int64
+		bgCPUTime     atomic.Int64
 	}
 
 	// mheap_.lock. This level of indirection makes it possible
@@ -5,12 +5,22 @@
 package runtime
 
 import (
+	"runtime/internal/atomic"
 	"runtime/internal/sys"
 	"unsafe"
 )
 
 const pageCachePages = 8 * unsafe.Sizeof(pageCache{}.cache)
 
+// pageCacheHits and pageCacheMisses count span allocations small enough
+// to be served from a P's page cache that were and weren't, respectively,
+// served from it without taking the heap lock. An allocation that finds
+// the cache empty and refills it is a miss.
+var (
+	pageCacheHits   atomic.Uint64
+	pageCacheMisses atomic.Uint64
+)
+
 // pageCache represents a per-p cache of pages the allocator can
 // allocate from without a lock. More specifically, it represents
 // a pageCachePages*pageSize chunk of memory with 0 or more free
@@ -157,6 +157,10 @@ var (
 	xbuckets atomic.UnsafePointer // *bucket, mutex profile buckets
 	buckhash atomic.UnsafePointer // *buckhashArray
 
+	// mProfBuckets is the number of memory profile buckets, which
+	// are never freed. Incremented with profInsertLock held.
+	mProfBuckets atomic.Uint64
+
 	mProfCycle mProfCycleHolder
 )
 
@@ -314,6 +318,7 @@ func stkbucket(typ bucketType, size uintptr, stk []uintptr, alloc bool) *bucket
 	var allnext *atomic.UnsafePointer
 	if typ == memProfile {
 		allnext = &mbuckets
+		mProfBuckets.Add(1)
 	} else if typ == mutexProfile {
 		allnext = &xbuckets
 	} else {
@@ -414,10 +419,11 @@ func mProf_PostSweep() {
 	unlock(&profMemActiveLock)
 }
 
-// Called by malloc to record a profiled block.
-func mProf_Malloc(p unsafe.Pointer, size uintptr) {
+// Called by malloc to record a profiled block. skip is the number of
+// frames, starting with mProf_Malloc itself, to omit from the stack.
+func mProf_Malloc(p unsafe.Pointer, size uintptr, skip int) {
 	var stk [maxStack]uintptr
-	nstk := callers(4, stk[:])
+	nstk := callers(skip, stk[:])
 
 	index := (mProfCycle.read() + 2) % uint32(len(memRecord{}.future))
 
@@ -11,6 +11,12 @@ import (
 	"unsafe"
 )
 
+// spanSetContentions counts the times a spanSet pop failed to claim
+// the head because of a concurrent push or pop, and had to retry.
+// Since the mcentral span lists are spanSets, this is the closest
+// thing they have to lock contention.
+var spanSetContentions atomic.Uint64
+
 // A spanSet is a set of *mspans.
 //
 // spanSet is safe for concurrent push and pop operations.
@@ -168,6 +174,7 @@ claimLoop:
 			if b.index.cas(headtail, makeHeadTailIndex(want+1, tail)) {
 				break claimLoop
 			}
+			spanSetContentions.Add(1)
 			headtail = b.index.load()
 			head, tail = headtail.split()
 		}
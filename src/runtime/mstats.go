@@ -26,6 +26,9 @@ type mstats struct {
 	// Statistics about GC overhead.
 	gcMiscSys sysMemStat // updated atomically or during STW
 
+	// Statistics about type metadata.
+	itabSys sysMemStat // itabs built at run time by interface conversions
+
 	// Miscellaneous statistics.
 	other_sys sysMemStat // updated atomically or during STW
 
@@ -43,6 +46,43 @@ type mstats struct {
 	last_gc_nanotime uint64 // last gc (monotonic time)
 	lastHeapInUse    uint64 // heapInUse at mark termination of the previous GC
 
+	// gcAssistTime is the total nanoseconds goroutines have spent
+	// performing GC assists, accumulated at the end of each GC cycle.
+	gcAssistTime atomic.Int64
+
+	// gcAssistWaitTime is the total nanoseconds goroutines have spent
+	// parked on the assist queue waiting for background mark workers to
+	// provide assist credit. Unlike gcAssistTime, it's updated as soon as
+	// each goroutine is woken.
+	gcAssistWaitTime atomic.Int64
+
+	// gcDedicatedMarkTime is the total nanoseconds spent in dedicated
+	// and fractional mark workers, gcIdleMarkTime is the total
+	// nanoseconds spent in idle mark workers, and gcPauseTime is the
+	// total CPU nanoseconds lost to GC stop-the-world pauses, that is,
+	// pause time multiplied by the number of Ps stopped. All are
+	// accumulated at the end of each GC cycle.
+	gcDedicatedMarkTime atomic.Int64
+	gcIdleMarkTime      atomic.Int64
+	gcPauseTime         atomic.Int64
+
+	// gcScanWork is the total bytes of heap, stacks, and globals
+	// scanned by the GC, accumulated at the end of each GC cycle.
+	gcScanWork atomic.Int64
+
+	// gcStartCPUStats is the CPU time breakdown as of the start of
+	// the current GC cycle. Protected by sched.lock.
+	gcStartCPUStats cpuStats
+
+	// cycleAllocMean holds the float64 bits of the mean size of heap
+	// objects allocated during the most recently completed GC cycle.
+	// cycleAllocBytes and cycleAllocs are the total bytes and count of
+	// heap objects allocated as of the end of that cycle, and are only
+	// accessed with the world stopped.
+	cycleAllocMean  atomic.Uint64
+	cycleAllocBytes uint64
+	cycleAllocs     uint64
+
 	enablegc bool
 
 	_ uint32 // ensure gcPauseDist is aligned.
@@ -52,6 +92,23 @@ type mstats struct {
 	//
 	// Each individual pause is counted separately, unlike pause_ns.
 	gcPauseDist timeHistogram
+
+	// gcCycleDist represents the distribution of the wall-clock
+	// durations of completed GC cycles, from the start of sweep
+	// termination to the end of mark termination.
+	gcCycleDist timeHistogram
+
+	// gcSweepTermPauseDist and gcMarkTermPauseDist split gcPauseDist
+	// into the pauses for sweep termination and for mark termination,
+	// including mark termination attempts that restart concurrent mark.
+	gcSweepTermPauseDist timeHistogram
+	gcMarkTermPauseDist  timeHistogram
+
+	// gcMarkDist represents the distribution of the wall-clock
+	// durations of the concurrent mark phase of completed GC cycles,
+	// from the end of sweep termination to the start of the final
+	// mark termination.
+	gcMarkDist timeHistogram
 }
 
 var memstats mstats
@@ -338,6 +395,22 @@ func init() {
 		println(offset)
 		throw("memstats.gcPauseDist not aligned to 8 bytes")
 	}
+	if offset := unsafe.Offsetof(memstats.gcCycleDist); offset%8 != 0 {
+		println(offset)
+		throw("memstats.gcCycleDist not aligned to 8 bytes")
+	}
+	if offset := unsafe.Offsetof(memstats.gcSweepTermPauseDist); offset%8 != 0 {
+		println(offset)
+		throw("memstats.gcSweepTermPauseDist not aligned to 8 bytes")
+	}
+	if offset := unsafe.Offsetof(memstats.gcMarkTermPauseDist); offset%8 != 0 {
+		println(offset)
+		throw("memstats.gcMarkTermPauseDist not aligned to 8 bytes")
+	}
+	if offset := unsafe.Offsetof(memstats.gcMarkDist); offset%8 != 0 {
+		println(offset)
+		throw("memstats.gcMarkDist not aligned to 8 bytes")
+	}
 	// Ensure the size of heapStatsDelta causes adjacent fields/slots (e.g.
 	// [3]heapStatsDelta) to be 8-byte aligned.
 	if size := unsafe.Sizeof(heapStatsDelta{}); size%8 != 0 {
@@ -431,8 +504,8 @@ func readmemstats_m(stats *MemStats) {
 
 	totalMapped := gcController.heapInUse.load() + gcController.heapFree.load() + gcController.heapReleased.load() +
 		memstats.stacks_sys.load() + memstats.mspan_sys.load() + memstats.mcache_sys.load() +
-		memstats.buckhash_sys.load() + memstats.gcMiscSys.load() + memstats.other_sys.load() +
-		stackInUse + gcWorkBufInUse + gcProgPtrScalarBitsInUse
+		memstats.buckhash_sys.load() + memstats.gcMiscSys.load() + memstats.itabSys.load() +
+		memstats.other_sys.load() + stackInUse + gcWorkBufInUse + gcProgPtrScalarBitsInUse
 
 	heapGoal := gcController.heapGoal()
 
@@ -530,7 +603,9 @@ func readmemstats_m(stats *MemStats) {
 	// to the memory management system, but we track this memory
 	// at a more granular level in the runtime.
 	stats.GCSys = memstats.gcMiscSys.load() + gcWorkBufInUse + gcProgPtrScalarBitsInUse
-	stats.OtherSys = memstats.other_sys.load()
+	// MemStats predates the separate accounting of itabs, which
+	// used to be included in OtherSys.
+	stats.OtherSys = memstats.itabSys.load() + memstats.other_sys.load()
 	stats.NextGC = heapGoal
 	stats.LastGC = memstats.last_gc_unix
 	stats.PauseTotalNs = memstats.pause_total_ns
@@ -622,6 +697,25 @@ func flushallmcaches() {
 	}
 }
 
+// recordCycleAllocMean updates cycleAllocMean with the mean size of the
+// heap objects allocated since the last time it was called. It's called
+// once at the end of each GC cycle. If no objects were allocated, the
+// previous mean is retained.
+//
+// The world must be stopped.
+func (m *mstats) recordCycleAllocMean() {
+	assertWorldStopped()
+
+	var a heapStatsAggregate
+	a.compute()
+	if a.totalAllocs > m.cycleAllocs {
+		mean := float64(a.totalAllocated-m.cycleAllocBytes) / float64(a.totalAllocs-m.cycleAllocs)
+		m.cycleAllocMean.Store(float64bits(mean))
+	}
+	m.cycleAllocBytes = a.totalAllocated
+	m.cycleAllocs = a.totalAllocs
+}
+
 // sysMemStat represents a global system statistic that is managed atomically.
 //
 // This type must structurally be a uint64 so that mstats aligns with MemStats.
@@ -666,6 +760,10 @@ type heapStatsDelta struct {
 	// These are all uint64 because they're cumulative, and could quickly wrap
 	// around otherwise.
 	tinyAllocCount  uint64                  // number of tiny allocations
+	tinyBlockCount  uint64                  // number of tiny allocator blocks
+	ifaceAllocCount uint64                  // number of allocations boxing values in interfaces
+	fastAllocCount  uint64                  // number of small allocations satisfied by nextFreeFast
+	slowAllocCount  uint64                  // number of small allocations that needed nextFree
 	largeAlloc      uint64                  // bytes allocated for large objects
 	largeAllocCount uint64                  // number of large object allocations
 	smallAllocCount [_NumSizeClasses]uint64 // number of allocs for small objects
@@ -673,6 +771,12 @@ type heapStatsDelta struct {
 	largeFreeCount  uint64                  // number of frees for large objects (>maxSmallSize)
 	smallFreeCount  [_NumSizeClasses]uint64 // number of frees for small objects (<=maxSmallSize)
 
+	// Scavenger stats.
+	//
+	// This is uint64 because it's cumulative.
+	scavenged   uint64 // bytes of memory returned to the underlying system
+	recommitted uint64 // bytes of scavenged memory reused for allocation
+
 	// NOTE: This struct must be a multiple of 8 bytes in size because it
 	// is stored in an array. If it's not, atomic accesses to the above
 	// fields may be unaligned and fail on 32-bit platforms.
@@ -688,6 +792,10 @@ func (a *heapStatsDelta) merge(b *heapStatsDelta) {
 	a.inPtrScalarBits += b.inPtrScalarBits
 
 	a.tinyAllocCount += b.tinyAllocCount
+	a.tinyBlockCount += b.tinyBlockCount
+	a.ifaceAllocCount += b.ifaceAllocCount
+	a.fastAllocCount += b.fastAllocCount
+	a.slowAllocCount += b.slowAllocCount
 	a.largeAlloc += b.largeAlloc
 	a.largeAllocCount += b.largeAllocCount
 	for i := range b.smallAllocCount {
@@ -698,6 +806,9 @@ func (a *heapStatsDelta) merge(b *heapStatsDelta) {
 	for i := range b.smallFreeCount {
 		a.smallFreeCount[i] += b.smallFreeCount[i]
 	}
+
+	a.scavenged += b.scavenged
+	a.recommitted += b.recommitted
 }
 
 // consistentHeapStats represents a set of various memory statistics
@@ -886,3 +997,51 @@ func (m *consistentHeapStats) read(out *heapStatsDelta) {
 
 	releasem(mp)
 }
+
+// cpuStats is a breakdown of the CPU time available to the runtime
+// into the classes reported by the /cpu/classes metrics, in nanoseconds.
+type cpuStats struct {
+	gcAssistTime        int64 // GC assists
+	gcDedicatedMarkTime int64 // dedicated and fractional GC mark workers
+	gcIdleMarkTime      int64 // idle GC mark workers
+	gcPauseTime         int64 // GC pauses, times the number of Ps stopped
+	scavengeTime        int64 // background and assist scavenging
+	idleTime            int64 // Ps on the idle P list
+	userTime            int64 // everything else
+	totalTime           int64 // wall time times GOMAXPROCS
+}
+
+// readCPUStats returns the breakdown of CPU time as of now.
+//
+// The GC classes are only updated at the end of each GC cycle, so
+// while a cycle is in progress, readCPUStats returns the breakdown
+// as of the start of the cycle instead, keeping all the classes
+// consistent with each other.
+//
+// sched.lock must be held, which also keeps the GC from changing phase.
+func readCPUStats(now int64) cpuStats {
+	assertLockHeld(&sched.lock)
+
+	if gcphase != _GCoff {
+		return memstats.gcStartCPUStats
+	}
+	s := cpuStats{
+		gcAssistTime:        memstats.gcAssistTime.Load(),
+		gcDedicatedMarkTime: memstats.gcDedicatedMarkTime.Load(),
+		gcIdleMarkTime:      memstats.gcIdleMarkTime.Load(),
+		gcPauseTime:         memstats.gcPauseTime.Load(),
+		scavengeTime:        mheap_.pages.scav.assistCPUTime.Load() + mheap_.pages.scav.bgCPUTime.Load(),
+		idleTime:            sched.idleTime.Load(),
+		totalTime:           sched.totaltime + (now-sched.procresizetime)*int64(gomaxprocs),
+	}
+	for pp := sched.pidle.ptr(); pp != nil; pp = pp.link.ptr() {
+		s.idleTime += now - pp.idleStart
+	}
+	s.userTime = s.totalTime - (s.gcAssistTime + s.gcDedicatedMarkTime +
+		s.gcIdleMarkTime + s.gcPauseTime + s.scavengeTime + s.idleTime)
+	if s.userTime < 0 {
+		// The other classes are estimates, and may overlap a little.
+		s.userTime = 0
+	}
+	return s
+}
@@ -202,6 +202,10 @@ func wbBufFlush(dst *uintptr, src uintptr) {
 	})
 }
 
+// wbShades counts the objects that write barriers have greyed, as
+// found when their buffers are flushed by wbBufFlush1.
+var wbShades atomic.Uint64
+
 // wbBufFlush1 flushes p's write barrier buffer to the GC work queue.
 //
 // This must not have write barriers because it is part of the write
@@ -247,6 +251,7 @@ func wbBufFlush1(_p_ *p) {
 	// un-shaded stacks and flush after each stack scan.
 	gcw := &_p_.gcw
 	pos := 0
+	shaded := 0
 	for _, ptr := range ptrs {
 		if ptr < minLegalPointer {
 			// nil pointers are very common, especially
@@ -268,6 +273,7 @@ func wbBufFlush1(_p_ *p) {
 			continue
 		}
 		mbits.setMarked()
+		shaded++
 
 		// Mark span.
 		arena, pageIdx, pageMask := pageIndexOf(span.base())
@@ -285,6 +291,7 @@ func wbBufFlush1(_p_ *p) {
 
 	// Enqueue the greyed objects.
 	gcw.putBatch(ptrs[:pos])
+	wbShades.Add(int64(shaded))
 
 	_p_.wbBuf.reset()
 }
@@ -412,6 +412,24 @@ func freedefer(d *_defer) {
 	mp, pp = nil, nil
 }
 
+// deferPoolLen returns the number of _defer records in the per-P
+// and central defer pools.
+//
+// The per-P pools are read without synchronization, like the free G
+// lists in gcount, so the result may be inconsistent.
+func deferPoolLen() uint64 {
+	var n uint64
+	for _, pp := range allp {
+		n += uint64(len(pp.deferpool))
+	}
+	lock(&sched.deferlock)
+	for d := sched.deferpool; d != nil; d = d.link {
+		n++
+	}
+	unlock(&sched.deferlock)
+	return n
+}
+
 // Separate function so that it can split stack.
 // Windows otherwise runs out of stack space.
 func freedeferpanic() {
@@ -302,6 +302,7 @@ func asyncPreempt()
 func asyncPreempt2() {
 	gp := getg()
 	gp.asyncSafePoint = true
+	sched.preemptions.Add(1)
 	if gp.preemptStop {
 		mcall(preemptPark)
 	} else {
@@ -1185,6 +1185,8 @@ func stopTheWorldWithSema() {
 		throw("stopTheWorld: holding locks")
 	}
 
+	sched.stwStart = nanotime()
+
 	lock(&sched.lock)
 	sched.stopwait = gomaxprocs
 	atomic.Store(&sched.gcwaiting, 1)
@@ -1205,8 +1207,9 @@ func stopTheWorldWithSema() {
 		}
 	}
 	// stop idle P's
+	now := nanotime()
 	for {
-		p := pidleget()
+		p, _ := pidleget(now)
 		if p == nil {
 			break
 		}
@@ -1298,6 +1301,8 @@ func startTheWorldWithSema(emitTraceEvent bool) int64 {
 
 	// Capture start-the-world time before doing clean-up tasks.
 	startTime := nanotime()
+	sched.stwCount.Add(1)
+	sched.stwTotalTime.Add(startTime - sched.stwStart)
 	if emitTraceEvent {
 		traceGCSTWDone()
 	}
@@ -1528,6 +1533,8 @@ found:
 		m.freelink = sched.freem
 		sched.freem = m
 	}
+	lockContentions += m.ncontended
+	chanBlockTime += m.chanBlockTime
 	unlock(&sched.lock)
 
 	atomic.Xadd64(&ncgocall, int64(m.ncgocall))
@@ -2277,7 +2284,7 @@ func startm(_p_ *p, spinning bool) {
 	mp := acquirem()
 	lock(&sched.lock)
 	if _p_ == nil {
-		_p_ = pidleget()
+		_p_, _ = pidleget(0)
 		if _p_ == nil {
 			unlock(&sched.lock)
 			if spinning {
@@ -2400,7 +2407,7 @@ func handoffp(_p_ *p) {
 	// The scheduler lock cannot be held when calling wakeNetPoller below
 	// because wakeNetPoller may call wakep which may call startm.
 	when := nobarrierWakeTime(_p_)
-	pidleput(_p_)
+	pidleput(_p_, 0)
 	unlock(&sched.lock)
 
 	if when != 0 {
@@ -2733,7 +2740,7 @@ top:
 	if releasep() != _p_ {
 		throw("findrunnable: wrong p")
 	}
-	pidleput(_p_)
+	now = pidleput(_p_, now)
 	unlock(&sched.lock)
 
 	// Delicate dance: thread transitions from spinning to non-spinning
@@ -2828,7 +2835,8 @@ top:
 		}
 		list := netpoll(delay) // block until new work is available
 		atomic.Store64(&sched.pollUntil, 0)
-		atomic.Store64(&sched.lastpoll, uint64(nanotime()))
+		now = nanotime()
+		atomic.Store64(&sched.lastpoll, uint64(now))
 		if faketime != 0 && list.empty() {
 			// Using fake time and nothing is ready; stop M.
 			// When all M's stop, checkdead will call timejump.
@@ -2836,7 +2844,7 @@ top:
 			goto top
 		}
 		lock(&sched.lock)
-		_p_ = pidleget()
+		_p_, _ = pidleget(now)
 		unlock(&sched.lock)
 		if _p_ == nil {
 			injectglist(&list)
@@ -2950,7 +2958,9 @@ func stealWork(now int64) (gp *g, inheritTime bool, rnow, pollUntil int64, newWo
 
 			// Don't bother to attempt to steal if p2 is idle.
 			if !idlepMask.read(enum.position()) {
+				sched.stealAttempts.Add(1)
 				if gp := runqsteal(pp, p2, stealTimersOrRunNextG); gp != nil {
+					sched.stealSuccesses.Add(1)
 					return gp, false, now, pollUntil, ranTimer
 				}
 			}
@@ -2972,7 +2982,7 @@ func checkRunqsNoP(allpSnapshot []*p, idlepMaskSnapshot pMask) *p {
 	for id, p2 := range allpSnapshot {
 		if !idlepMaskSnapshot.read(uint32(id)) && !runqempty(p2) {
 			lock(&sched.lock)
-			pp := pidleget()
+			pp, _ := pidleget(0)
 			unlock(&sched.lock)
 			if pp != nil {
 				return pp
@@ -3038,7 +3048,7 @@ func checkIdleGCNoP() (*p, *g) {
 	// the assumption in gcControllerState.findRunnableGCWorker that an
 	// empty gcBgMarkWorkerPool is only possible if gcMarkDone is running.
 	lock(&sched.lock)
-	pp := pidleget()
+	pp, now := pidleget(0)
 	if pp == nil {
 		unlock(&sched.lock)
 		return nil, nil
@@ -3046,14 +3056,14 @@ func checkIdleGCNoP() (*p, *g) {
 
 	// Now that we own a P, gcBlackenEnabled can't change (as it requires STW).
 	if gcBlackenEnabled == 0 || !gcController.addIdleMarkWorker() {
-		pidleput(pp)
+		now = pidleput(pp, now)
 		unlock(&sched.lock)
 		return nil, nil
 	}
 
 	node := (*gcBgMarkWorkerNode)(gcBgMarkWorkerPool.pop())
 	if node == nil {
-		pidleput(pp)
+		now = pidleput(pp, now)
 		unlock(&sched.lock)
 		gcController.removeIdleMarkWorker()
 		return nil, nil
@@ -3910,7 +3920,7 @@ func exitsyscallfast_reacquired() {
 
 func exitsyscallfast_pidle() bool {
 	lock(&sched.lock)
-	_p_ := pidleget()
+	_p_, _ := pidleget(0)
 	if _p_ != nil && atomic.Load(&sched.sysmonwait) != 0 {
 		atomic.Store(&sched.sysmonwait, 0)
 		notewakeup(&sched.sysmonnote)
@@ -3935,7 +3945,7 @@ func exitsyscall0(gp *g) {
 	lock(&sched.lock)
 	var _p_ *p
 	if schedEnabled(gp) {
-		_p_ = pidleget()
+		_p_, _ = pidleget(0)
 	}
 	var locked bool
 	if _p_ == nil {
@@ -4147,6 +4157,7 @@ func newproc1(fn *funcval, callergp *g, callerpc uintptr) *g {
 	if isSystemGoroutine(newg, false) {
 		atomic.Xadd(&sched.ngsys, +1)
 	} else {
+		sched.goroutinesCreated.Add(1)
 		// Only user goroutines inherit pprof labels.
 		if _g_.m.curg != nil {
 			newg.labels = _g_.m.curg.labels
@@ -4808,6 +4819,9 @@ func procresize(nprocs int32) *p {
 		sched.totaltime += int64(old) * (now - sched.procresizetime)
 	}
 	sched.procresizetime = now
+	if old != 0 && old != nprocs {
+		sched.gomaxprocsChanges.Add(1)
+	}
 
 	maskWords := (nprocs + 31) / 32
 
@@ -4910,7 +4924,7 @@ func procresize(nprocs int32) *p {
 		}
 		p.status = _Pidle
 		if runqempty(p) {
-			pidleput(p)
+			pidleput(p, now)
 		} else {
 			p.m.set(mget())
 			p.link.set(runnablePs)
@@ -5679,7 +5693,8 @@ func updateTimerPMask(pp *p) {
 	unlock(&pp.timersLock)
 }
 
-// pidleput puts p to on the _Pidle list.
+// pidleput puts p to on the _Pidle list. now must be a relatively recent call
+// to nanotime or zero. Returns now or the current time if now was zero.
 //
 // This releases ownership of p. Once sched.lock is released it is no longer
 // safe to use p.
@@ -5689,7 +5704,7 @@ func updateTimerPMask(pp *p) {
 // May run during STW, so write barriers are not allowed.
 //
 //go:nowritebarrierrec
-func pidleput(_p_ *p) {
+func pidleput(_p_ *p, now int64) int64 {
 	assertLockHeld(&sched.lock)
 
 	if !runqempty(_p_) {
@@ -5700,16 +5715,24 @@ func pidleput(_p_ *p) {
 	_p_.link = sched.pidle
 	sched.pidle.set(_p_)
 	atomic.Xadd(&sched.npidle, 1) // TODO: fast atomic
+	sched.pidleTransitions.Add(1)
+	if now == 0 {
+		now = nanotime()
+	}
+	_p_.idleStart = now
+	return now
 }
 
 // pidleget tries to get a p from the _Pidle list, acquiring ownership.
+// now must be a relatively recent call to nanotime or zero. Returns now or
+// the current time if now was zero.
 //
 // sched.lock must be held.
 //
 // May run during STW, so write barriers are not allowed.
 //
 //go:nowritebarrierrec
-func pidleget() *p {
+func pidleget(now int64) (*p, int64) {
 	assertLockHeld(&sched.lock)
 
 	_p_ := sched.pidle.ptr()
@@ -5719,8 +5742,12 @@ func pidleget() *p {
 		idlepMask.clear(_p_.id)
 		sched.pidle = _p_.link
 		atomic.Xadd(&sched.npidle, -1) // TODO: fast atomic
+		if now == 0 {
+			now = nanotime()
+		}
+		sched.idleTime.Add(now - _p_.idleStart)
 	}
-	return _p_
+	return _p_, now
 }
 
 // runqempty reports whether _p_ has no Gs on its local run queue.
@@ -167,6 +167,24 @@ type mutex struct {
 	key uintptr
 }
 
+// lockContentions counts the calls to lock, across all runtime
+// mutexes, that found the mutex already held and were made by Ms
+// that have since exited. Other Ms count their own, in
+// m.ncontended. Protected by sched.lock.
+var lockContentions uint64
+
+// totalLockContentions returns the number of calls to lock, across all
+// runtime mutexes and all Ms, that found the mutex already held.
+func totalLockContentions() uint64 {
+	lock(&sched.lock)
+	n := lockContentions
+	for mp := allm; mp != nil; mp = mp.alllink {
+		n += mp.ncontended
+	}
+	unlock(&sched.lock)
+	return n
+}
+
 // sleep and wakeup on one-time events.
 // before any calls to notesleep or notewakeup,
 // must call noteclear to initialize the Note.
@@ -551,6 +569,8 @@ type m struct {
 	needextram    bool
 	traceback     uint8
 	ncgocall      uint64      // number of cgo calls in total
+	ncontended    uint64      // number of calls to lock that found the mutex already held
+	chanBlockTime int64       // nanoseconds goroutines run by this m spent blocked on channels
 	ncgo          int32       // number of cgo calls currently in progress
 	cgoCallersUse uint32      // if non-zero, cgoCallers in use temporarily
 	cgoCallers    *cgoCallers // cgo traceback if crashing in cgo call
@@ -603,6 +623,7 @@ type p struct {
 	id          int32
 	status      uint32 // one of pidle/prunning/...
 	link        puintptr
+	idleStart   int64      // nanotime when put on the idle P list; protected by sched.lock
 	schedtick   uint32     // incremented on every scheduler call
 	syscalltick uint32     // incremented on every system call
 	sysmontick  sysmontick // last tick observed by sysmon
@@ -760,6 +781,40 @@ type schedt struct {
 	lastpoll  uint64 // time of last network poll, 0 if currently polling
 	pollUntil uint64 // time to which current poll is sleeping
 
+	// stwCount is the number of completed stop-the-world events and
+	// stwTotalTime is their total duration in nanoseconds, measured from
+	// the start of stopTheWorldWithSema until startTheWorldWithSema has
+	// restarted all Ps.
+	stwCount     atomic.Uint64
+	stwTotalTime atomic.Int64
+
+	// gomaxprocsChanges is the number of times procresize has changed
+	// gomaxprocs after the scheduler was initialized.
+	gomaxprocsChanges atomic.Uint64
+
+	// pidleTransitions is the number of times a P has been put on
+	// the idle P list.
+	pidleTransitions atomic.Uint64
+
+	// idleTime is the total nanoseconds Ps have spent on the idle P
+	// list, not counting Ps that are on it now. See readCPUStats.
+	idleTime atomic.Int64
+
+	// preemptions is the number of times a goroutine has been
+	// preempted, either synchronously at a stack check or
+	// asynchronously by a signal.
+	preemptions atomic.Uint64
+
+	// goroutinesCreated is the number of non-system goroutines
+	// created by newproc1.
+	goroutinesCreated atomic.Uint64
+
+	// stealAttempts is the number of times stealWork has tried to
+	// steal goroutines from another P's run queue, and stealSuccesses
+	// is the number of those attempts that took at least one.
+	stealAttempts  atomic.Uint64
+	stealSuccesses atomic.Uint64
+
 	lock mutex
 
 	// When increasing nmidle, nmidlelocked, nmsys, or nmfreed, be
@@ -821,6 +876,10 @@ type schedt struct {
 	sysmonwait uint32
 	sysmonnote note
 
+	// stwStart is the nanotime() at which the current stop-the-world
+	// began. It is only meaningful while the world is stopped.
+	stwStart int64
+
 	// safepointFn should be called on each P at the next GC
 	// safepoint if p.runSafePointFn is set.
 	safePointFn   func(*p)
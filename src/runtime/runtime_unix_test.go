@@ -12,10 +12,12 @@ package runtime_test
 
 import (
 	"runtime"
+	"runtime/metrics"
 	"sync"
 	"sync/atomic"
 	"syscall"
 	"testing"
+	"time"
 )
 
 func TestGoroutineProfile(t *testing.T) {
@@ -54,3 +56,48 @@ func TestGoroutineProfile(t *testing.T) {
 	atomic.StoreUint32(&stop, 1)
 	wg.Wait()
 }
+
+func TestReadMetricsInSyscall(t *testing.T) {
+	read := func() uint64 {
+		s := []metrics.Sample{{Name: "/sched/goroutines/in-syscall:goroutines"}}
+		metrics.Read(s)
+		return s[0].Value.Uint64()
+	}
+	var p [2]int
+	if err := syscall.Pipe(p[:]); err != nil {
+		t.Fatal(err)
+	}
+	defer syscall.Close(p[0])
+	defer syscall.Close(p[1])
+
+	// Block several goroutines reading from the pipe, directly with
+	// syscall.Read so that they don't use the network poller.
+	before := read()
+	const n = 4
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var b [1]byte
+			syscall.Read(p[0], b[:])
+		}()
+	}
+	peak := read()
+	for deadline := time.Now().Add(5 * time.Second); peak < before+n && time.Now().Before(deadline); {
+		time.Sleep(time.Millisecond)
+		peak = read()
+	}
+	if peak < before+n {
+		t.Errorf("in-syscall goroutines went from %d to %d with %d goroutines blocked reading a pipe", before, peak, n)
+	}
+
+	// Unblock them.
+	if _, err := syscall.Write(p[1], make([]byte, n)); err != nil {
+		t.Fatal(err)
+	}
+	wg.Wait()
+	if after := read(); after >= peak {
+		t.Errorf("in-syscall goroutines stayed at %d after unblocking %d goroutines", after, n)
+	}
+}
@@ -154,6 +154,7 @@ func selectgo(cas0 *scase, order0 *uint16, pc0 *uintptr, nsends, nrecvs int, blo
 	if blockprofilerate > 0 {
 		t0 = cputicks()
 	}
+	var parkStart int64
 
 	// The compiler rewrites selects that statically have
 	// only 0 or 1 cases plus default into simpler constructs.
@@ -325,7 +326,9 @@ func selectgo(cas0 *scase, order0 *uint16, pc0 *uintptr, nsends, nrecvs int, blo
 	// changes and when we set gp.activeStackChans is not safe for
 	// stack shrinking.
 	atomic.Store8(&gp.parkingOnChan, 1)
+	parkStart = nanotime()
 	gopark(selparkcommit, nil, waitReasonSelect, traceEvGoBlockSelect, 1)
+	gp.m.chanBlockTime += nanotime() - parkStart
 	gp.activeStackChans = false
 
 	sellock(scases, lockorder)
@@ -1054,6 +1054,7 @@ func newstack() {
 		if thisg.m.p == 0 && thisg.m.locks == 0 {
 			throw("runtime: g is running but p is not")
 		}
+		sched.preemptions.Add(1)
 
 		if gp.preemptShrink {
 			// We're at a synchronous safe point now, so
@@ -1109,6 +1110,9 @@ func newstack() {
 
 	// The concurrent GC will not scan the stack while we are doing the copy since
 	// the gp is in a Gcopystack status.
+	if newsize > oldsize {
+		stackGrowths.Add(1)
+	}
 	copystack(gp, newsize)
 	if stackDebug >= 1 {
 		print("stack grow done\n")
@@ -1211,6 +1215,7 @@ func shrinkstack(gp *g) {
 		print("shrinking stack ", oldsize, "->", newsize, "\n")
 	}
 
+	stackShrinks.Add(1)
 	copystack(gp, newsize)
 }
 
@@ -1437,6 +1442,12 @@ func morestackc() {
 	throw("attempt to execute system stack code on user stack")
 }
 
+// stackGrowths is the number of times a goroutine stack has been grown.
+var stackGrowths atomic.Uint64
+
+// stackShrinks is the number of times a goroutine stack has been shrunk.
+var stackShrinks atomic.Uint64
+
 // startingStackSize is the amount of stack that new goroutines start with.
 // It is a power of 2, and between _FixedStack and maxstacksize, inclusive.
 // startingStackSize is updated every GC by tracking the average size of
@@ -22,6 +22,7 @@ func init() {
 	register("GCFairness2", GCFairness2)
 	register("GCSys", GCSys)
 	register("GCPhys", GCPhys)
+	register("GCSweepDone", GCSweepDone)
 	register("DeferLiveness", DeferLiveness)
 	register("GCZombie", GCZombie)
 	register("GCMemoryLimit", GCMemoryLimit)
@@ -57,6 +58,17 @@ func GCSys() {
 	fmt.Printf("OK\n")
 }
 
+// GCSweepDone runs a few complete GC cycles, including sweeping.
+// It is run with GODEBUG=gcpacertrace=1 so the caller can check that
+// the end of each sweep phase is reported.
+func GCSweepDone() {
+	for i := 0; i < 3; i++ {
+		workthegc()
+		runtime.GC()
+	}
+	fmt.Printf("OK\n")
+}
+
 var sink []byte
 
 func workthegc() []byte {
@@ -0,0 +1,53 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !plan9 && !windows
+// +build !plan9,!windows
+
+package main
+
+/*
+#include <unistd.h>
+
+static void inCgoSleep(void) {
+	usleep(500000);
+}
+*/
+import "C"
+
+import (
+	"fmt"
+	"runtime/metrics"
+	"sync"
+	"time"
+)
+
+func init() {
+	register("InCgoMetric", InCgoMetric)
+}
+
+func InCgoMetric() {
+	const n = 4
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			C.inCgoSleep()
+		}()
+	}
+	defer wg.Wait()
+
+	s := []metrics.Sample{{Name: "/sched/threads/in-cgo:threads"}}
+	deadline := time.Now().Add(400 * time.Millisecond)
+	for time.Now().Before(deadline) {
+		metrics.Read(s)
+		if s[0].Value.Uint64() >= n {
+			fmt.Println("OK")
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	fmt.Printf("/sched/threads/in-cgo:threads = %d, want at least %d\n", s[0].Value.Uint64(), n)
+}
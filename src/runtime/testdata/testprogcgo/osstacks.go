@@ -0,0 +1,44 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+/*
+static void osStacksNop(void) {}
+*/
+import "C"
+
+import (
+	"fmt"
+	"runtime/metrics"
+	"sync"
+)
+
+func init() {
+	register("OSStacksMetric", OSStacksMetric)
+}
+
+func OSStacksMetric() {
+	// Make cgo calls from several goroutines at once, so that
+	// some of them need threads of their own.
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				C.osStacksNop()
+			}
+		}()
+	}
+	wg.Wait()
+
+	s := []metrics.Sample{{Name: "/memory/os-stacks:objects"}}
+	metrics.Read(s)
+	if n := s[0].Value.Uint64(); n == 0 {
+		fmt.Println("/memory/os-stacks:objects is zero in a cgo program")
+		return
+	}
+	fmt.Println("OK")
+}
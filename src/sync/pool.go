@@ -50,6 +50,14 @@ type Pool struct {
 	victim     unsafe.Pointer // local from previous cycle
 	victimSize uintptr        // size of victims array
 
+	// victimObjects is the number of objects in victim, as counted
+	// by poolCount less those Get has taken since poolCleanup demoted
+	// it, and demotedSize is victim's size at that time. Together
+	// they let poolCleanup report how many objects it drops without
+	// walking victim. victimObjects is updated atomically.
+	victimObjects uintptr
+	demotedSize   uintptr
+
 	// New optionally specifies a function to generate
 	// a value when Get would otherwise return nil.
 	// It may not be changed concurrently with calls to Get.
@@ -60,6 +68,7 @@ type Pool struct {
 type poolLocalInternal struct {
 	private any       // Can be used only by the respective P.
 	shared  poolChain // Local P can pushHead/popHead; any P can popTail.
+	objects int       // Objects Put by, less those taken by, the respective P. Used only by it.
 }
 
 type poolLocal struct {
@@ -105,6 +114,7 @@ func (p *Pool) Put(x any) {
 	} else {
 		l.shared.pushHead(x)
 	}
+	l.objects++
 	runtime_procUnpin()
 	if race.Enabled {
 		race.Enable()
@@ -131,9 +141,11 @@ func (p *Pool) Get() any {
 		// the head over the tail for temporal locality of
 		// reuse.
 		x, _ = l.shared.popHead()
-		if x == nil {
-			x = p.getSlow(pid)
-		}
+	}
+	if x != nil {
+		l.objects--
+	} else {
+		x = p.getSlow(pid)
 	}
 	runtime_procUnpin()
 	if race.Enabled {
@@ -156,6 +168,7 @@ func (p *Pool) getSlow(pid int) any {
 	for i := 0; i < int(size); i++ {
 		l := indexLocal(locals, (pid+i+1)%int(size))
 		if x, _ := l.shared.popTail(); x != nil {
+			indexLocal(locals, pid).objects--
 			return x
 		}
 	}
@@ -171,11 +184,13 @@ func (p *Pool) getSlow(pid int) any {
 	l := indexLocal(locals, pid)
 	if x := l.private; x != nil {
 		l.private = nil
+		atomic.AddUintptr(&p.victimObjects, ^uintptr(0))
 		return x
 	}
 	for i := 0; i < int(size); i++ {
 		l := indexLocal(locals, (pid+i)%int(size))
 		if x, _ := l.shared.popTail(); x != nil {
+			atomic.AddUintptr(&p.victimObjects, ^uintptr(0))
 			return x
 		}
 	}
@@ -228,23 +243,30 @@ func (p *Pool) pinSlow() (*poolLocal, int) {
 	return &local[pid], pid
 }
 
-func poolCleanup() {
+// poolCleanup returns the number of objects it dropped from victim caches.
+func poolCleanup() (dropped uint64) {
 	// This function is called with the world stopped, at the beginning of a garbage collection.
 	// It must not allocate and probably should not call any runtime functions.
 
 	// Because the world is stopped, no pool user can be in a
 	// pinned section (in effect, this has all Ps pinned).
 
-	// Drop victim caches from all pools.
+	// Drop victim caches from all pools, counting the objects
+	// in them for the runtime's metrics.
 	for _, p := range oldPools {
+		dropped += uint64(p.victimObjects)
 		p.victim = nil
 		p.victimSize = 0
+		p.victimObjects = 0
+		p.demotedSize = 0
 	}
 
-	// Move primary cache to victim cache.
+	// Move primary cache to victim cache. Put and Get stop counting
+	// the objects in it here; poolCount picks up their counts.
 	for _, p := range allPools {
 		p.victim = p.local
 		p.victimSize = p.localSize
+		p.demotedSize = p.localSize
 		p.local = nil
 		p.localSize = 0
 	}
@@ -252,6 +274,24 @@ func poolCleanup() {
 	// The pools with non-empty primary caches now have non-empty
 	// victim caches and no pools have primary caches.
 	oldPools, allPools = allPools, nil
+	return dropped
+}
+
+// poolCount adds up the objects the last poolCleanup moved to victim
+// caches. It is called once the world restarts after poolCleanup and
+// before the next poolCleanup, so that counting them, which takes time
+// proportional to the number of pools times GOMAXPROCS, happens outside
+// the stop-the-world pause.
+func poolCount() {
+	// Nothing writes to the per-P counts of a victim cache, but Get
+	// may already be taking objects from it.
+	for _, p := range oldPools {
+		n := 0
+		for i := 0; i < int(p.demotedSize); i++ {
+			n += indexLocal(p.victim, i).objects
+		}
+		atomic.AddUintptr(&p.victimObjects, uintptr(n))
+	}
 }
 
 var (
@@ -268,7 +308,7 @@ var (
 )
 
 func init() {
-	runtime_registerPoolCleanup(poolCleanup)
+	runtime_registerPoolCleanup(poolCleanup, poolCount)
 }
 
 func indexLocal(l unsafe.Pointer, i int) *poolLocal {
@@ -277,7 +317,7 @@ func indexLocal(l unsafe.Pointer, i int) *poolLocal {
 }
 
 // Implemented in runtime.
-func runtime_registerPoolCleanup(cleanup func())
+func runtime_registerPoolCleanup(cleanup func() uint64, count func())
 func runtime_procPin() int
 func runtime_procUnpin()
 
@@ -327,6 +327,44 @@ func BenchmarkPoolSTW(b *testing.B) {
 	b.ReportMetric(float64(pauses[len(pauses)*50/100]), "p50-ns/STW")
 }
 
+func BenchmarkPoolCleanup(b *testing.B) {
+	// Take control of GC.
+	defer debug.SetGCPercent(debug.SetGCPercent(-1))
+	// Pool cleanup does per-P work in every pool.
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(64))
+
+	var mstats runtime.MemStats
+	var pauses []uint64
+
+	// Many pools, each holding a few items, so that every
+	// cleanup both drops and demotes a populated cache.
+	pools := make([]Pool, 1000)
+	for i := 0; i < b.N; i++ {
+		var item any = 42
+		for j := range pools {
+			for k := 0; k < 10; k++ {
+				pools[j].Put(item)
+			}
+		}
+		// Do a GC.
+		runtime.GC()
+		// Record pause time.
+		runtime.ReadMemStats(&mstats)
+		pauses = append(pauses, mstats.PauseNs[(mstats.NumGC+255)%256])
+	}
+
+	// Get pause time stats.
+	sort.Slice(pauses, func(i, j int) bool { return pauses[i] < pauses[j] })
+	var total uint64
+	for _, ns := range pauses {
+		total += ns
+	}
+	// ns/op for this benchmark is average STW time.
+	b.ReportMetric(float64(total)/float64(b.N), "ns/op")
+	b.ReportMetric(float64(pauses[len(pauses)*95/100]), "p95-ns/STW")
+	b.ReportMetric(float64(pauses[len(pauses)*50/100]), "p50-ns/STW")
+}
+
 func BenchmarkPoolExpensiveNew(b *testing.B) {
 	// Populate a pool with items that are expensive to construct
 	// to stress pool cleanup and subsequent reconstruction.